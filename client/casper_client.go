@@ -1,57 +1,90 @@
 package client
 
 import (
-	"encoding/hex"
+	"context"
 	"errors"
 	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
 	"github.com/JFJun/casperlabs-go/common"
+	"github.com/JFJun/casperlabs-go/deploy"
 	"github.com/JFJun/casperlabs-go/keys"
 	"github.com/JFJun/casperlabs-go/model"
+	"math/big"
+	"time"
 )
 
 type CasperClient struct {
-	url           string
-	casper        *common.RpcClient
-	eventStoreApi string
+	url     string
+	casper  *common.RpcClient
+	timeout time.Duration
+}
+
+type Option func(cc *CasperClient)
+
+/*
+每个rpc请求的超时时间,默认为common.DefaultTimeout
+d<=0时不设置超时,只受ctx控制
+*/
+func WithTimeout(d time.Duration) Option {
+	return func(cc *CasperClient) {
+		cc.timeout = d
+	}
 }
 
 /*
 仅支持http,https
+eventStoreApi: 已废弃,所有查询都通过节点的rpc完成,该参数不再生效,保留只是为了兼容旧的调用方式
 */
-func New(url, eventStoreApi string) *CasperClient {
+func New(url, eventStoreApi string, opts ...Option) *CasperClient {
 	cc := new(CasperClient)
 	cc.url = url
-	cc.eventStoreApi = eventStoreApi
+	cc.timeout = common.DefaultTimeout
+	for _, opt := range opts {
+		opt(cc)
+	}
 
 	cc.casper = common.Dial(cc.url, "", "")
+	cc.casper.SetTimeout(cc.timeout)
 	return cc
 }
 
 /*
 这其实就是根据txid查询交易信息
 deployHash就是txid
+返回完整的deploy以及在区块中的执行结果,deploy尚未执行时ExecutionResults为空
 */
-func (cc *CasperClient) GetDeployByHash(deployHash string) {
-	url := fmt.Sprintf("%s/deploy/%s", cc.eventStoreApi, deployHash)
-	req := common.HttpGet(url)
-	data, err := req.Bytes()
+func (cc *CasperClient) GetDeployByHash(deployHash string) (*model.DeployInfo, error) {
+	return cc.GetDeployByHashContext(context.Background(), deployHash)
+}
+
+// GetDeployByHashContext 同GetDeployByHash,ctx用于超时和取消
+func (cc *CasperClient) GetDeployByHashContext(ctx context.Context, deployHash string) (*model.DeployInfo, error) {
+	var res model.DeployInfo
+	params := map[string]interface{}{
+		"deploy_hash": deployHash,
+	}
+	err := cc.casper.SendRequestWithContext(ctx, "info_get_deploy", &res, params)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, fmt.Errorf("rpc info_get_deploy error: %w", err)
 	}
-	fmt.Println(string(data))
+	return &res, nil
 }
 
 /*
 根据区块hash获取区块的信息
 */
 func (cc *CasperClient) GetBlockInfoByHash(blockHash string) (*model.ChainBlock, error) {
+	return cc.GetBlockInfoByHashContext(context.Background(), blockHash)
+}
+
+// GetBlockInfoByHashContext 同GetBlockInfoByHash,ctx用于超时和取消
+func (cc *CasperClient) GetBlockInfoByHashContext(ctx context.Context, blockHash string) (*model.ChainBlock, error) {
 	var res model.ChainBlock
 	params := make(map[string]interface{})
 	params["block_identifier"] = map[string]interface{}{
 		"Hash": blockHash,
 	}
-	err := cc.casper.SendRequest("chain_get_block", &res, params)
+	err := cc.casper.SendRequestWithContext(ctx, "chain_get_block", &res, params)
 	if err != nil {
 		return nil, err
 	}
@@ -62,12 +95,17 @@ func (cc *CasperClient) GetBlockInfoByHash(blockHash string) (*model.ChainBlock,
 根据区块height获取区块的信息
 */
 func (cc *CasperClient) GetBlockInfoByHeight(height int64) (*model.ChainBlock, error) {
+	return cc.GetBlockInfoByHeightContext(context.Background(), height)
+}
+
+// GetBlockInfoByHeightContext 同GetBlockInfoByHeight,ctx用于超时和取消
+func (cc *CasperClient) GetBlockInfoByHeightContext(ctx context.Context, height int64) (*model.ChainBlock, error) {
 	var res model.ChainBlock
 	params := make(map[string]interface{})
 	params["block_identifier"] = map[string]interface{}{
 		"Height": height,
 	}
-	err := cc.casper.SendRequest("chain_get_block", &res, params)
+	err := cc.casper.SendRequestWithContext(ctx, "chain_get_block", &res, params)
 	if err != nil {
 		return nil, err
 	}
@@ -76,16 +114,26 @@ func (cc *CasperClient) GetBlockInfoByHeight(height int64) (*model.ChainBlock, e
 }
 
 func (cc *CasperClient) GetLatestBlockInfo() (*model.ChainBlock, error) {
+	return cc.GetLatestBlockInfoContext(context.Background())
+}
+
+// GetLatestBlockInfoContext 同GetLatestBlockInfo,ctx用于超时和取消
+func (cc *CasperClient) GetLatestBlockInfoContext(ctx context.Context) (*model.ChainBlock, error) {
 	var res model.ChainBlock
-	err := cc.casper.SendRequest("chain_get_block", &res, nil)
+	err := cc.casper.SendRequestWithContext(ctx, "chain_get_block", &res, nil)
 	if err != nil {
 		return nil, err
 	}
 	return &res, err
 }
 func (cc *CasperClient) GetLatestBlockHeight() (int64, error) {
+	return cc.GetLatestBlockHeightContext(context.Background())
+}
+
+// GetLatestBlockHeightContext 同GetLatestBlockHeight,ctx用于超时和取消
+func (cc *CasperClient) GetLatestBlockHeightContext(ctx context.Context) (int64, error) {
 	var res model.ChainBlock
-	err := cc.casper.SendRequest("chain_get_block", &res, nil)
+	err := cc.casper.SendRequestWithContext(ctx, "chain_get_block", &res, nil)
 	if err != nil {
 		return -1, err
 	}
@@ -93,21 +141,31 @@ func (cc *CasperClient) GetLatestBlockHeight() (int64, error) {
 }
 
 func (cc *CasperClient) GetBlockTransferByHeight(height int64) (*model.BlockTransfer, error) {
+	return cc.GetBlockTransferByHeightContext(context.Background(), height)
+}
+
+// GetBlockTransferByHeightContext 同GetBlockTransferByHeight,ctx用于超时和取消
+func (cc *CasperClient) GetBlockTransferByHeightContext(ctx context.Context, height int64) (*model.BlockTransfer, error) {
 	var res model.BlockTransfer
 	params := make(map[string]interface{})
 	params["block_identifier"] = map[string]interface{}{
 		"Height": height,
 	}
-	err := cc.casper.SendRequest("chain_get_block_transfers", &res, params)
+	err := cc.casper.SendRequestWithContext(ctx, "chain_get_block_transfers", &res, params)
 	if err != nil {
-		return nil, fmt.Errorf("rpc chain_get_block_transfers error: %v", err)
+		return nil, fmt.Errorf("rpc chain_get_block_transfers error: %w", err)
 	}
 	return &res, nil
 }
 
 func (cc *CasperClient) GetStatus() (*model.ChainStatus, error) {
+	return cc.GetStatusContext(context.Background())
+}
+
+// GetStatusContext 同GetStatus,ctx用于超时和取消
+func (cc *CasperClient) GetStatusContext(ctx context.Context) (*model.ChainStatus, error) {
 	var status model.ChainStatus
-	err := cc.casper.SendRequest("info_get_status", &status, nil)
+	err := cc.casper.SendRequestWithContext(ctx, "info_get_status", &status, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -115,47 +173,64 @@ func (cc *CasperClient) GetStatus() (*model.ChainStatus, error) {
 }
 
 func (cc *CasperClient) GetBalance(address string) (string, error) {
-	return cc.GetBalanceWithHeight(address, -1)
+	return cc.GetBalanceContext(context.Background(), address)
 }
+
+// GetBalanceContext 同GetBalance,ctx用于超时和取消
+func (cc *CasperClient) GetBalanceContext(ctx context.Context, address string) (string, error) {
+	return cc.GetBalanceWithHeightContext(ctx, address, -1)
+}
+
 func (cc *CasperClient) GetBalanceWithHeight(address string, height int64) (balance string, err error) {
+	return cc.GetBalanceWithHeightContext(context.Background(), address, height)
+}
+
+// GetBalanceWithHeightContext 同GetBalanceWithHeight,ctx用于超时和取消
+func (cc *CasperClient) GetBalanceWithHeightContext(ctx context.Context, address string, height int64) (balance string, err error) {
 	var lb *model.ChainBlock
 	if height < 0 {
-		lb, err = cc.GetLatestBlockInfo()
+		lb, err = cc.GetLatestBlockInfoContext(ctx)
 	} else {
-		lb, err = cc.GetBlockInfoByHeight(height)
+		lb, err = cc.GetBlockInfoByHeightContext(ctx, height)
+	}
+	if err != nil {
+		return "", fmt.Errorf("balance get state root hash error: %w", err)
 	}
-	if err != nil || lb == nil {
-		return "", fmt.Errorf("balance get state root hash error")
+	if lb == nil {
+		return "", errors.New("balance get state root hash error")
 	}
-	accountHash, err := keys.AddressToAccountHash(address)
+	pk, err := cl.ParsePublicKey(address)
 	if err != nil {
 		return "", err
 	}
-	accountHashStr := hex.EncodeToString(accountHash[:])
 	stateRootHash := lb.Block.Header.StateRootHash
-	key := fmt.Sprintf("account-hash-%s", accountHashStr)
-	bs, err := cc.GetBlockState(stateRootHash, key, nil)
+	bs, err := cc.GetBlockStateContext(ctx, stateRootHash, pk.AccountHash().String(), nil)
 	if err != nil {
 		return "", err
 	}
 	balanceUref := bs.StoredValue.Account.MainPurse
-	if balanceUref == "" {
+	if balanceUref == nil {
 		return "", errors.New("balance uref is null")
 	}
 	var ab model.AccountBalance
 	bp := map[string]interface{}{
 		"state_root_hash": stateRootHash,
-		"purse_uref":      balanceUref,
+		"purse_uref":      balanceUref.String(),
 	}
-	err = cc.casper.SendRequest("state_get_balance", &ab, bp)
+	err = cc.casper.SendRequestWithContext(ctx, "state_get_balance", &ab, bp)
 	if err != nil {
-		return "", fmt.Errorf("rpc state_get_balance error: %v", err)
+		return "", fmt.Errorf("rpc state_get_balance error: %w", err)
 	}
 
 	return ab.BalanceValue, err
 }
 
 func (cc *CasperClient) GetBlockState(stateRootHash, key string, path []string) (*model.BlockState, error) {
+	return cc.GetBlockStateContext(context.Background(), stateRootHash, key, path)
+}
+
+// GetBlockStateContext 同GetBlockState,ctx用于超时和取消
+func (cc *CasperClient) GetBlockStateContext(ctx context.Context, stateRootHash, key string, path []string) (*model.BlockState, error) {
 	var res model.BlockState
 	params := make(map[string]interface{})
 	params["state_root_hash"] = stateRootHash
@@ -165,13 +240,155 @@ func (cc *CasperClient) GetBlockState(stateRootHash, key string, path []string)
 	} else {
 		params["path"] = path
 	}
-	err := cc.casper.SendRequest("state_get_item", &res, params)
+	err := cc.casper.SendRequestWithContext(ctx, "state_get_item", &res, params)
 	if err != nil {
-		return nil, fmt.Errorf("rpc state_get_item error: %v", err)
+		return nil, fmt.Errorf("rpc state_get_item error: %w", err)
 	}
 	return &res, nil
 }
 
-func (cc *CasperClient) Transfer() {
-	//todo
+/*
+根据地址(公钥hex)获取账户信息，包括关联的key和签名权重阈值
+*/
+func (cc *CasperClient) GetAccount(address string) (*model.BlockStateAccount, error) {
+	return cc.GetAccountContext(context.Background(), address)
+}
+
+// GetAccountContext 同GetAccount,ctx用于超时和取消
+func (cc *CasperClient) GetAccountContext(ctx context.Context, address string) (*model.BlockStateAccount, error) {
+	pk, err := cl.ParsePublicKey(address)
+	if err != nil {
+		return nil, err
+	}
+	lb, err := cc.GetLatestBlockInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := cc.GetBlockStateContext(ctx, lb.Block.Header.StateRootHash, pk.AccountHash().String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &bs.StoredValue.Account, nil
+}
+
+/*
+检查离线签名后带回的deploy:
+hash没有被修改,签名有效,签名者是账户自身或者关联的key,并且签名权重之和达到deployment阈值
+*/
+func (cc *CasperClient) CheckDeploySigners(d *deploy.Deploy) error {
+	return cc.CheckDeploySignersContext(context.Background(), d)
+}
+
+// CheckDeploySignersContext 同CheckDeploySigners,ctx用于超时和取消
+func (cc *CasperClient) CheckDeploySignersContext(ctx context.Context, d *deploy.Deploy) error {
+	if err := d.CheckHash(); err != nil {
+		return err
+	}
+	account, err := cc.GetAccountContext(ctx, d.Header.Account.String())
+	if err != nil {
+		return err
+	}
+	weights := make(map[cl.AccountHash]int)
+	associated := make([]cl.AccountHash, 0, len(account.AssociatedKeys))
+	for _, k := range account.AssociatedKeys {
+		weights[k.AccountHash] = k.Weight
+		associated = append(associated, k.AccountHash)
+	}
+	if err = d.CheckSigners(associated...); err != nil {
+		return err
+	}
+	total := 0
+	for _, a := range d.Approvals {
+		ah := a.Signer.AccountHash()
+		total += weights[ah]
+		//同一个key重复签名只计算一次
+		delete(weights, ah)
+	}
+	if total < account.ActionThresholds.Deployment {
+		return fmt.Errorf("approval weight %d is lower than the deployment threshold %d",
+			total, account.ActionThresholds.Deployment)
+	}
+	return nil
+}
+
+/*
+发送已签名的deploy,返回deploy hash
+*/
+func (cc *CasperClient) PutDeploy(d *deploy.Deploy) (string, error) {
+	return cc.PutDeployContext(context.Background(), d)
+}
+
+// PutDeployContext 同PutDeploy,ctx用于超时和取消
+func (cc *CasperClient) PutDeployContext(ctx context.Context, d *deploy.Deploy) (string, error) {
+	//离线签名的deploy可能被修改过，发送前重新计算hash
+	if err := d.CheckHash(); err != nil {
+		return "", err
+	}
+	var res model.PutDeployResult
+	params := map[string]interface{}{
+		"deploy": d,
+	}
+	err := cc.casper.SendRequestWithContext(ctx, "account_put_deploy", &res, params)
+	if err != nil {
+		return "", fmt.Errorf("rpc account_put_deploy error: %w", err)
+	}
+	return res.DeployHash, nil
+}
+
+/*
+CSPR转账,返回deploy hash
+from: 发送方,需要同时包含公钥和私钥
+target: 接收方的地址(公钥hex)或者account hash
+amount,paymentAmount: 单位为motes
+*/
+func (cc *CasperClient) Transfer(from keys.KeyHolder, target string, amount *big.Int, transferId uint64,
+	paymentAmount *big.Int, chainName string) (string, error) {
+	return cc.TransferContext(context.Background(), from, target, amount, transferId, paymentAmount, chainName)
+}
+
+// TransferContext 同Transfer,ctx用于超时和取消
+func (cc *CasperClient) TransferContext(ctx context.Context, from keys.KeyHolder, target string, amount *big.Int, transferId uint64,
+	paymentAmount *big.Int, chainName string) (string, error) {
+	accountHex, err := from.AccountHex()
+	if err != nil {
+		return "", err
+	}
+	account, err := cl.ParsePublicKey(accountHex)
+	if err != nil {
+		return "", err
+	}
+	targetValue, err := transferTarget(target)
+	if err != nil {
+		return "", err
+	}
+	session, err := deploy.NewTransfer(amount, targetValue, transferId)
+	if err != nil {
+		return "", err
+	}
+	payment, err := deploy.StandardPayment(paymentAmount)
+	if err != nil {
+		return "", err
+	}
+	d, err := deploy.MakeDeploy(deploy.NewDeployParam(account, chainName), session, payment)
+	if err != nil {
+		return "", err
+	}
+	if err = d.Sign(from); err != nil {
+		return "", err
+	}
+	return cc.PutDeployContext(ctx, d)
+}
+
+/*
+转账目标可以是公钥地址,也可以是account hash
+*/
+func transferTarget(target string) (cl.CLTypedAndToBytes, error) {
+	if keys.IsAccount(target) {
+		return cl.ParsePublicKey(target)
+	}
+	accountHash, err := cl.ParseAccountHash(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer target %s: %v", target, err)
+	}
+	return accountHash, nil
 }
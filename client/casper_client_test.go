@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/JFJun/casperlabs-go/common"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCasperClient_Context(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := New(server.URL, "").GetStatusContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("deadline error: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := New(server.URL, "").GetDeployByHashContext(ctx, "hash"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancel error: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := New(server.URL, "", WithTimeout(20*time.Millisecond)).GetLatestBlockHeight(); err == nil {
+		t.Fatal("expect timeout error")
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("timeout is not applied: %v", time.Since(start))
+	}
+}
+
+func TestCasperClient_GetBalanceContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32001,"message":"block not known"}}`))
+	}))
+	defer server.Close()
+	address := "0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c"
+	if _, err := New(server.URL, "").GetBalanceWithHeight(address, 100); !errors.Is(err, common.ErrNoSuchBlock) {
+		t.Fatalf("block error: %v", err)
+	}
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := New(slow.URL, "").GetBalanceContext(ctx, address); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("deadline error: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/JFJun/casperlabs-go/common"
+	"github.com/JFJun/casperlabs-go/deploy"
+	"github.com/JFJun/casperlabs-go/model"
+	"strings"
+	"time"
+)
+
+var (
+	//超过ttl仍未执行,deploy不会再被打包
+	ErrDeployExpired = errors.New("deploy expired without execution")
+	//以下错误用于errors.Is判断DeployFailedError的失败原因
+	ErrOutOfGas = errors.New("out of gas")
+	//账户余额不足,例如转账金额大于余额
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	//payment的金额不足以支付执行费用,不是余额不足
+	ErrInsufficientPayment = errors.New("insufficient payment")
+)
+
+/*
+轮询参数,首次等待Interval,之后每次翻倍,最大为MaxInterval
+Deploy为提交的deploy,节点返回no such deploy时用它的timestamp和ttl判断是否过期,
+为空时只能使用节点返回的deploy判断
+*/
+type WaitOptions struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Deploy      *deploy.Deploy
+}
+
+func DefaultWaitOptions() *WaitOptions {
+	return &WaitOptions{
+		Interval:    2 * time.Second,
+		MaxInterval: 30 * time.Second,
+	}
+}
+
+// deploy的执行结果
+type DeployExecution struct {
+	BlockHash string
+	// 消耗的gas,单位为motes
+	Cost   string
+	Result *model.ExecutionResultDetail
+}
+
+// deploy已经被打包,但是执行失败
+type DeployFailedError struct {
+	DeployHash string
+	BlockHash  string
+	Cost       string
+	Message    string
+}
+
+func (e *DeployFailedError) Error() string {
+	return fmt.Sprintf("deploy %s failed in block %s: %s", e.DeployHash, e.BlockHash, e.Message)
+}
+
+/*
+根据节点返回的错误信息判断失败原因,例如:
+Out of gas error -> ErrOutOfGas
+ApiError::Mint(InsufficientFunds) [65024] -> ErrInsufficientBalance
+Insufficient payment -> ErrInsufficientPayment
+*/
+func (e *DeployFailedError) Is(target error) bool {
+	msg := strings.ToLower(e.Message)
+	switch target {
+	case ErrOutOfGas:
+		return strings.Contains(msg, "out of gas")
+	case ErrInsufficientBalance:
+		return strings.Contains(msg, "insufficientfunds") || strings.Contains(msg, "insufficient funds") ||
+			strings.Contains(msg, "insufficient balance")
+	case ErrInsufficientPayment:
+		return strings.Contains(msg, "insufficient payment")
+	}
+	return false
+}
+
+/*
+等待deploy被执行,定时调用info_get_deploy直到:
+1. 有执行结果,成功时返回区块hash和消耗的gas,失败时返回*DeployFailedError
+2. 超过deploy的ttl仍未执行,返回ErrDeployExpired
+3. ctx被取消或者超时,返回ctx.Err()
+节点返回no such deploy时继续等待,opts中没有Deploy时无法判断是否过期,需要用ctx设置超时时间
+opts为空时使用DefaultWaitOptions
+*/
+func (cc *CasperClient) WaitForDeploy(ctx context.Context, deployHash string, opts *WaitOptions) (*DeployExecution, error) {
+	if opts == nil {
+		opts = DefaultWaitOptions()
+	}
+	interval, maxInterval := opts.Interval, opts.MaxInterval
+	if interval <= 0 {
+		interval = DefaultWaitOptions().Interval
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+	for {
+		info, err := cc.GetDeployByHashContext(ctx, deployHash)
+		switch {
+		case err == nil:
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case errors.Is(err, common.ErrNoSuchDeploy):
+			//deploy刚提交时可能还没有同步到当前节点,继续等待
+			info = &model.DeployInfo{}
+		default:
+			return nil, err
+		}
+		if info.Executed() {
+			return deployExecution(deployHash, info.ExecutionResults[0])
+		}
+		d := info.Deploy
+		if opts.Deploy != nil {
+			d = opts.Deploy
+		}
+		if d != nil && d.Header != nil && expired(d.Header, time.Now()) {
+			return nil, ErrDeployExpired
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// 比较时间差,timestamp+ttl可能溢出
+func expired(h *deploy.DeployHeader, now time.Time) bool {
+	ms := uint64(now.UnixNano() / int64(time.Millisecond))
+	return h.Timestamp <= ms && ms-h.Timestamp > h.TTL
+}
+
+func deployExecution(deployHash string, r model.BlockExecutionResult) (*DeployExecution, error) {
+	if !r.Result.IsSuccess() {
+		detail := r.Result.Detail()
+		if detail == nil {
+			return nil, fmt.Errorf("deploy %s has an empty execution result", deployHash)
+		}
+		return nil, &DeployFailedError{
+			DeployHash: deployHash,
+			BlockHash:  r.BlockHash,
+			Cost:       detail.Cost,
+			Message:    detail.ErrorMessage,
+		}
+	}
+	return &DeployExecution{
+		BlockHash: r.BlockHash,
+		Cost:      r.Result.Success.Cost,
+		Result:    r.Result.Success,
+	}, nil
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"github.com/JFJun/casperlabs-go/common"
+	"github.com/JFJun/casperlabs-go/deploy"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testNode answers info_get_deploy, the execution result shows up after pending polls
+func testNode(t *testing.T, timestamp uint64, pending int32, result string) (*httptest.Server, *int32) {
+	account, _ := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	session, _ := deploy.NewTransfer(big.NewInt(2500000000), account, 1)
+	payment, _ := deploy.StandardPayment(big.NewInt(10000))
+	d, err := deploy.MakeDeploy(deploy.NewDeployParam(account, "casper-test").SetTimestamp(timestamp), session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployJson, _ := json.Marshal(d)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results := "[]"
+		if atomic.AddInt32(&calls, 1) > pending {
+			results = `[{"block_hash":"6f168ef1d9bfcca97146b4925924e9594dba03a3fe30952653867ecc5fda5746","result":` + result + `}]`
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"api_version":"1.0.0","deploy":` + string(deployJson) +
+			`,"execution_results":` + results + `}}`))
+	}))
+	return server, &calls
+}
+
+func nowMillis() uint64 {
+	return uint64(time.Now().UnixNano() / int64(time.Millisecond))
+}
+
+func TestCasperClient_WaitForDeploy(t *testing.T) {
+	opts := &WaitOptions{Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond}
+	success := `{"Success":{"effect":{"operations":[],"transforms":[]},"transfers":[],"cost":"100000000"}}`
+	server, calls := testNode(t, nowMillis(), 3, success)
+	defer server.Close()
+	execution, err := New(server.URL, "").WaitForDeploy(context.Background(), "hash", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(calls) != 4 || execution.Cost != "100000000" || execution.BlockHash == "" {
+		t.Fatalf("execution error: %d %+v", *calls, execution)
+	}
+
+	failure := `{"Failure":{"effect":{"operations":[],"transforms":[]},"transfers":[],"cost":"10000","error_message":"Out of gas error"}}`
+	server, _ = testNode(t, nowMillis(), 0, failure)
+	defer server.Close()
+	_, err = New(server.URL, "").WaitForDeploy(context.Background(), "hash", opts)
+	var failed *DeployFailedError
+	if !errors.As(err, &failed) || failed.Cost != "10000" || !errors.Is(err, ErrOutOfGas) || errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("failure error: %v", err)
+	}
+
+	server, _ = testNode(t, nowMillis()-2*deploy.DefaultTTL, 100, success)
+	defer server.Close()
+	if _, err = New(server.URL, "").WaitForDeploy(context.Background(), "hash", opts); !errors.Is(err, ErrDeployExpired) {
+		t.Fatalf("expired error: %v", err)
+	}
+
+	server, _ = testNode(t, nowMillis(), 1000000, success)
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err = New(server.URL, "").WaitForDeploy(ctx, "hash", opts); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("context error: %v", err)
+	}
+}
+
+func TestCasperClient_WaitForDeploy_NotKnown(t *testing.T) {
+	opts := &WaitOptions{Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond}
+	success := `{"Success":{"effect":{"operations":[],"transforms":[]},"transfers":[],"cost":"100000000"}}`
+	node, _ := testNode(t, nowMillis(), 0, success)
+	defer node.Close()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"deploy not known"}}`))
+			return
+		}
+		node.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	execution, err := New(server.URL, "").WaitForDeploy(context.Background(), "hash", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&calls) != 3 || execution.Cost != "100000000" {
+		t.Fatalf("execution error: %d %+v", calls, execution)
+	}
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"state query failed"}}`))
+	}))
+	defer server.Close()
+	// -32000 is the generic server error of 1.0-1.3 nodes, it is a real failure
+	var rpcErr *common.RPCError
+	if _, err = New(server.URL, "").WaitForDeploy(context.Background(), "hash", opts); !errors.As(err, &rpcErr) || rpcErr.Code != -32000 {
+		t.Fatalf("rpc error: %v", err)
+	}
+}
+
+// the node never received the deploy, the expiry comes from the submitted deploy
+func TestCasperClient_WaitForDeploy_NeverReceived(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"deploy not known"}}`))
+	}))
+	defer server.Close()
+	account, _ := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	session, _ := deploy.NewTransfer(big.NewInt(2500000000), account, 1)
+	payment, _ := deploy.StandardPayment(big.NewInt(10000))
+	d, err := deploy.MakeDeploy(deploy.NewDeployParam(account, "casper-test").SetTimestamp(nowMillis()-2*deploy.DefaultTTL), session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	opts := &WaitOptions{Interval: time.Millisecond, Deploy: d}
+	if _, err = New(server.URL, "").WaitForDeploy(ctx, hex.EncodeToString(d.Hash), opts); !errors.Is(err, ErrDeployExpired) {
+		t.Fatalf("expired error: %v", err)
+	}
+
+	d.Header.TTL = math.MaxUint64
+	if expired(d.Header, time.Now()) {
+		t.Fatal("max ttl should not overflow")
+	}
+}
+
+func TestDeployFailedError_Is(t *testing.T) {
+	cases := []struct {
+		message string
+		target  error
+	}{
+		{"Out of gas error", ErrOutOfGas},
+		{"ApiError::Mint(InsufficientFunds) [65024]", ErrInsufficientBalance},
+		{"Insufficient funds", ErrInsufficientBalance},
+		{"Insufficient payment", ErrInsufficientPayment},
+		{"ApiError::User(1) [65537]", nil},
+	}
+	targets := []error{ErrOutOfGas, ErrInsufficientBalance, ErrInsufficientPayment}
+	for _, c := range cases {
+		err := &DeployFailedError{Message: c.message}
+		for _, target := range targets {
+			if errors.Is(err, target) != (target == c.target) {
+				t.Errorf("%q is %v: %v", c.message, target, errors.Is(err, target))
+			}
+		}
+	}
+}
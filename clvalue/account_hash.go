@@ -0,0 +1,80 @@
+package clvalue
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/JFJun/casperlabs-go/common/hexutil"
+	"strings"
+)
+
+const accountHashPrefix = "account-hash-"
+
+// AccountHash is the blake2b256 hash of the account public key,
+// as a CLValue it is a ByteArray(32)
+type AccountHash [32]byte
+
+// ParseAccountHash accepts account-hash-<64 hex> or the plain hex
+func ParseAccountHash(s string) (AccountHash, error) {
+	var ah AccountHash
+	s = strings.TrimPrefix(s, accountHashPrefix)
+	if hexutil.Has0xPrefix(s) {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return ah, fmt.Errorf("invalid account hash: %v", err)
+	}
+	if len(b) != len(ah) {
+		return ah, fmt.Errorf("invalid account hash len: %d", len(b))
+	}
+	copy(ah[:], b)
+	return ah, nil
+}
+
+func (ah AccountHash) Bytes() []byte {
+	return ah[:]
+}
+
+func (ah AccountHash) Hex() string {
+	return hex.EncodeToString(ah[:])
+}
+
+// String returns account-hash-<64 hex>
+func (ah AccountHash) String() string {
+	return accountHashPrefix + ah.Hex()
+}
+
+func (ah AccountHash) Key() *Key {
+	key, _ := NewAccountKey(ah[:])
+	return key
+}
+
+func (ah AccountHash) GetCLType() int {
+	return TagByteArray
+}
+
+func (ah AccountHash) CLTypeDescriptor() *CLType {
+	return NewByteArrayType(uint32(len(ah)))
+}
+
+func (ah AccountHash) ToBytes() []byte {
+	return ah.Bytes()
+}
+
+func (ah AccountHash) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ah.String())
+}
+
+func (ah *AccountHash) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := ParseAccountHash(s)
+	if err != nil {
+		return err
+	}
+	*ah = v
+	return nil
+}
@@ -0,0 +1,26 @@
+package clvalue
+
+// Any holds the raw bytes of a value of type Any
+type Any struct {
+	data []byte
+}
+
+func NewAny(data []byte) *Any {
+	b := make([]byte, len(data))
+	copy(b, data)
+	return &Any{
+		data: b,
+	}
+}
+
+func (a *Any) Value() []byte {
+	return a.data
+}
+
+func (a *Any) GetCLType() int {
+	return TagAny
+}
+
+func (a *Any) ToBytes() []byte {
+	return a.data
+}
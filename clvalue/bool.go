@@ -0,0 +1,26 @@
+package clvalue
+
+type Bool struct {
+	value bool
+}
+
+func NewBool(value bool) *Bool {
+	return &Bool{
+		value: value,
+	}
+}
+
+func (b *Bool) Value() bool {
+	return b.value
+}
+
+func (b *Bool) GetCLType() int {
+	return TagBool
+}
+
+func (b *Bool) ToBytes() []byte {
+	if b.value {
+		return []byte{1}
+	}
+	return []byte{0}
+}
@@ -0,0 +1,30 @@
+package clvalue
+
+// ByteArray is a fixed length byte array, serialized without length prefix
+type ByteArray struct {
+	data []byte
+}
+
+func NewByteArray(data []byte) *ByteArray {
+	b := make([]byte, len(data))
+	copy(b, data)
+	return &ByteArray{
+		data: b,
+	}
+}
+
+func (b *ByteArray) Value() []byte {
+	return b.data
+}
+
+func (b *ByteArray) GetCLType() int {
+	return TagByteArray
+}
+
+func (b *ByteArray) CLTypeDescriptor() *CLType {
+	return NewByteArrayType(uint32(len(b.data)))
+}
+
+func (b *ByteArray) ToBytes() []byte {
+	return b.data
+}
@@ -1,5 +1,13 @@
 package clvalue
 
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
 //func toByteNumber(bitSize uint32, signed bool, value *big.Int) ([]byte, error) {
 //	v, err := BigNumberFrom(value)
 //	if err != nil {
@@ -12,3 +20,91 @@ package clvalue
 //	fmt.Println(hex.EncodeToString(b[:]))
 //
 //}
+
+func ToBytesU8(v uint8) []byte {
+	return []byte{v}
+}
+
+func ToBytesU32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func ToBytesU64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
+
+// ToBytesArrayU8 serializes a byte slice prefixed with its u32 length
+func ToBytesArrayU8(b []byte) []byte {
+	return bytes.Join([][]byte{
+		ToBytesU32(uint32(len(b))),
+		b,
+	}, []byte{})
+}
+
+func ToBytesString(s string) []byte {
+	return ToBytesArrayU8([]byte(s))
+}
+
+var (
+	ErrEarlyEndOfStream = errors.New("early end of stream")
+	ErrLeftOverBytes    = errors.New("left over bytes")
+	ErrFormatting       = errors.New("formatting error")
+)
+
+func readBytes(data []byte, n int) ([]byte, []byte, error) {
+	if n < 0 || len(data) < n {
+		return nil, nil, ErrEarlyEndOfStream
+	}
+	return data[:n], data[n:], nil
+}
+
+func readU8(data []byte) (uint8, []byte, error) {
+	b, rest, err := readBytes(data, 1)
+	if err != nil {
+		return 0, nil, err
+	}
+	return b[0], rest, nil
+}
+
+func readU32(data []byte) (uint32, []byte, error) {
+	b, rest, err := readBytes(data, 4)
+	if err != nil {
+		return 0, nil, err
+	}
+	return binary.LittleEndian.Uint32(b), rest, nil
+}
+
+func readU64(data []byte) (uint64, []byte, error) {
+	b, rest, err := readBytes(data, 8)
+	if err != nil {
+		return 0, nil, err
+	}
+	return binary.LittleEndian.Uint64(b), rest, nil
+}
+
+// readArrayU8 reads a byte slice prefixed with its u32 length
+func readArrayU8(data []byte) ([]byte, []byte, error) {
+	l, rest, err := readU32(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(l) > uint64(len(rest)) {
+		return nil, nil, ErrEarlyEndOfStream
+	}
+	return readBytes(rest, int(l))
+}
+
+func readString(data []byte) (string, []byte, error) {
+	b, rest, err := readArrayU8(data)
+	if err != nil {
+		return "", nil, err
+	}
+	if !utf8.Valid(b) {
+		return "", nil, fmt.Errorf("%w: invalid utf-8 string", ErrFormatting)
+	}
+	return string(b), rest, nil
+}
@@ -0,0 +1,290 @@
+package clvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var clTypeNames = map[int]string{
+	TagBool:      "Bool",
+	TagI32:       "I32",
+	TagI64:       "I64",
+	TagU8:        "U8",
+	TagU32:       "U32",
+	TagU64:       "U64",
+	TagU128:      "U128",
+	TagU256:      "U256",
+	TagU512:      "U512",
+	TagUnit:      "Unit",
+	TagString:    "String",
+	TagKey:       "Key",
+	TagURef:      "URef",
+	TagOption:    "Option",
+	TagList:      "List",
+	TagByteArray: "ByteArray",
+	TagResult:    "Result",
+	TagMap:       "Map",
+	TagTuple1:    "Tuple1",
+	TagTuple2:    "Tuple2",
+	TagTuple3:    "Tuple3",
+	TagAny:       "Any",
+	TagPublicKey: "PublicKey",
+}
+
+var clTypeTags = func() map[string]int {
+	tags := make(map[string]int, len(clTypeNames))
+	for tag, name := range clTypeNames {
+		tags[name] = tag
+	}
+	return tags
+}()
+
+// CLType describes the type of a CLValue.
+// Inner holds the nested types of Option/List(1), Result/Map(2) and Tuple1/2/3(1-3),
+// Size is only used by ByteArray.
+type CLType struct {
+	Tag   int
+	Inner []*CLType
+	Size  uint32
+}
+
+// CLTypeDescriber is implemented by values whose type can not be described by the tag alone
+type CLTypeDescriber interface {
+	CLTypeDescriptor() *CLType
+}
+
+func NewCLType(tag int) *CLType {
+	return &CLType{
+		Tag: tag,
+	}
+}
+
+func NewOptionType(inner *CLType) *CLType {
+	return &CLType{
+		Tag:   TagOption,
+		Inner: []*CLType{inner},
+	}
+}
+
+func NewByteArrayType(size uint32) *CLType {
+	return &CLType{
+		Tag:  TagByteArray,
+		Size: size,
+	}
+}
+
+func NewListType(inner *CLType) *CLType {
+	return &CLType{
+		Tag:   TagList,
+		Inner: []*CLType{inner},
+	}
+}
+
+func NewResultType(ok, err *CLType) *CLType {
+	return &CLType{
+		Tag:   TagResult,
+		Inner: []*CLType{ok, err},
+	}
+}
+
+func NewMapType(key, value *CLType) *CLType {
+	return &CLType{
+		Tag:   TagMap,
+		Inner: []*CLType{key, value},
+	}
+}
+
+func NewTuple1Type(t1 *CLType) *CLType {
+	return &CLType{
+		Tag:   TagTuple1,
+		Inner: []*CLType{t1},
+	}
+}
+
+func NewTuple2Type(t1, t2 *CLType) *CLType {
+	return &CLType{
+		Tag:   TagTuple2,
+		Inner: []*CLType{t1, t2},
+	}
+}
+
+func NewTuple3Type(t1, t2, t3 *CLType) *CLType {
+	return &CLType{
+		Tag:   TagTuple3,
+		Inner: []*CLType{t1, t2, t3},
+	}
+}
+
+// TypeOf returns the full type descriptor of value
+func TypeOf(value CLTypedAndToBytes) *CLType {
+	if d, ok := value.(CLTypeDescriber); ok {
+		return d.CLTypeDescriptor()
+	}
+	return NewCLType(value.GetCLType())
+}
+
+func (t *CLType) Equal(other *CLType) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+	if t.Tag != other.Tag || t.Size != other.Size || len(t.Inner) != len(other.Inner) {
+		return false
+	}
+	for i := range t.Inner {
+		if !t.Inner[i].Equal(other.Inner[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the readable type, e.g. Option(U64), Map(String, U512), ByteArray(32)
+func (t *CLType) String() string {
+	name, ok := clTypeNames[t.Tag]
+	if !ok {
+		return fmt.Sprintf("Unknown(%d)", t.Tag)
+	}
+	if t.Tag == TagByteArray {
+		return fmt.Sprintf("%s(%d)", name, t.Size)
+	}
+	if len(t.Inner) == 0 {
+		return name
+	}
+	inner := make([]string, 0, len(t.Inner))
+	for _, it := range t.Inner {
+		inner = append(inner, it.String())
+	}
+	return name + "(" + strings.Join(inner, ", ") + ")"
+}
+
+// ToBytes encodes the type as tag followed by the nested types, ByteArray appends its u32 size
+func (t *CLType) ToBytes() []byte {
+	buf := []byte{byte(t.Tag)}
+	if t.Tag == TagByteArray {
+		buf = append(buf, ToBytesU32(t.Size)...)
+	}
+	for _, inner := range t.Inner {
+		buf = append(buf, inner.ToBytes()...)
+	}
+	return buf
+}
+
+func (t *CLType) MarshalJSON() ([]byte, error) {
+	name, ok := clTypeNames[t.Tag]
+	if !ok {
+		return nil, fmt.Errorf("unknown cl type tag: %d", t.Tag)
+	}
+	var v interface{}
+	switch t.Tag {
+	case TagOption, TagList:
+		if len(t.Inner) != 1 {
+			return nil, fmt.Errorf("%s type requires 1 inner type", name)
+		}
+		v = map[string]interface{}{name: t.Inner[0]}
+	case TagByteArray:
+		v = map[string]interface{}{name: t.Size}
+	case TagResult:
+		if len(t.Inner) != 2 {
+			return nil, fmt.Errorf("%s type requires 2 inner types", name)
+		}
+		v = map[string]interface{}{name: map[string]interface{}{
+			"ok":  t.Inner[0],
+			"err": t.Inner[1],
+		}}
+	case TagMap:
+		if len(t.Inner) != 2 {
+			return nil, fmt.Errorf("%s type requires 2 inner types", name)
+		}
+		v = map[string]interface{}{name: map[string]interface{}{
+			"key":   t.Inner[0],
+			"value": t.Inner[1],
+		}}
+	case TagTuple1, TagTuple2, TagTuple3:
+		if len(t.Inner) != t.Tag-TagTuple1+1 {
+			return nil, fmt.Errorf("%s type requires %d inner types", name, t.Tag-TagTuple1+1)
+		}
+		v = map[string]interface{}{name: t.Inner}
+	default:
+		v = name
+	}
+	return json.Marshal(v)
+}
+
+func (t *CLType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		tag, ok := clTypeTags[name]
+		if !ok || innerTypeCount(tag) != 0 || tag == TagByteArray {
+			return fmt.Errorf("invalid cl type: %s", name)
+		}
+		*t = CLType{Tag: tag}
+		return nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid cl type json: %s", string(data))
+	}
+	if len(obj) != 1 {
+		return fmt.Errorf("invalid cl type json: %s", string(data))
+	}
+	for name, raw := range obj {
+		tag, ok := clTypeTags[name]
+		if !ok {
+			return fmt.Errorf("invalid cl type: %s", name)
+		}
+		nt := CLType{Tag: tag}
+		switch tag {
+		case TagOption, TagList:
+			var inner CLType
+			if err := json.Unmarshal(raw, &inner); err != nil {
+				return err
+			}
+			nt.Inner = []*CLType{&inner}
+		case TagByteArray:
+			if err := json.Unmarshal(raw, &nt.Size); err != nil {
+				return fmt.Errorf("invalid byte array size: %v", err)
+			}
+		case TagResult:
+			var r struct {
+				Ok  *CLType `json:"ok"`
+				Err *CLType `json:"err"`
+			}
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return err
+			}
+			if r.Ok == nil || r.Err == nil {
+				return fmt.Errorf("invalid result type: %s", string(raw))
+			}
+			nt.Inner = []*CLType{r.Ok, r.Err}
+		case TagMap:
+			var m struct {
+				Key   *CLType `json:"key"`
+				Value *CLType `json:"value"`
+			}
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return err
+			}
+			if m.Key == nil || m.Value == nil {
+				return fmt.Errorf("invalid map type: %s", string(raw))
+			}
+			nt.Inner = []*CLType{m.Key, m.Value}
+		case TagTuple1, TagTuple2, TagTuple3:
+			if err := json.Unmarshal(raw, &nt.Inner); err != nil {
+				return err
+			}
+			if len(nt.Inner) != innerTypeCount(tag) {
+				return fmt.Errorf("invalid %s type: %s", name, string(raw))
+			}
+			for _, inner := range nt.Inner {
+				if inner == nil {
+					return fmt.Errorf("invalid %s type: %s", name, string(raw))
+				}
+			}
+		default:
+			return fmt.Errorf("invalid cl type json: %s", string(data))
+		}
+		*t = nt
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package clvalue
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestCLType_ToBytes(t *testing.T) {
+	cases := []struct {
+		clType *CLType
+		bytes  string
+		json   string
+	}{
+		{NewCLType(TagU512), "08", `"U512"`},
+		{NewOptionType(NewCLType(TagU64)), "0d05", `{"Option":"U64"}`},
+		{NewListType(NewCLType(TagString)), "0e0a", `{"List":"String"}`},
+		{NewByteArrayType(32), "0f20000000", `{"ByteArray":32}`},
+		{NewResultType(NewCLType(TagU8), NewCLType(TagString)), "10030a", `{"Result":{"err":"String","ok":"U8"}}`},
+		{NewMapType(NewCLType(TagString), NewCLType(TagU512)), "110a08", `{"Map":{"key":"String","value":"U512"}}`},
+		{NewTuple1Type(NewCLType(TagBool)), "1200", `{"Tuple1":["Bool"]}`},
+		{NewTuple2Type(NewCLType(TagU8), NewCLType(TagString)), "13030a", `{"Tuple2":["U8","String"]}`},
+		{NewTuple3Type(NewCLType(TagKey), NewCLType(TagURef), NewCLType(TagPublicKey)), "140b0c16", `{"Tuple3":["Key","URef","PublicKey"]}`},
+		{NewListType(NewOptionType(NewByteArrayType(32))), "0e0d0f20000000", `{"List":{"Option":{"ByteArray":32}}}`},
+	}
+	for _, c := range cases {
+		if actual := hex.EncodeToString(c.clType.ToBytes()); actual != c.bytes {
+			t.Errorf("%s bytes error, expect %s, actual %s", c.clType, c.bytes, actual)
+		}
+		data, err := json.Marshal(c.clType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != c.json {
+			t.Errorf("%s json error, expect %s, actual %s", c.clType, c.json, string(data))
+		}
+	}
+}
+
+func TestCLType_Equal(t *testing.T) {
+	a := NewMapType(NewCLType(TagString), NewListType(NewByteArrayType(32)))
+	b := NewMapType(NewCLType(TagString), NewListType(NewByteArrayType(32)))
+	c := NewMapType(NewCLType(TagString), NewListType(NewByteArrayType(33)))
+	if !a.Equal(b) {
+		t.Fatal("equal types should be equal")
+	}
+	if a.Equal(c) {
+		t.Fatal("different byte array size should not be equal")
+	}
+	if a.String() != "Map(String, List(ByteArray(32)))" {
+		t.Fatalf("type string error: %s", a.String())
+	}
+}
+
+func TestCLValue_ToBytes(t *testing.T) {
+	u512, err := NewU512(big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := NewCLValue(NewSomeOption(u512))
+	if actual := hex.EncodeToString(value.ToBytes()); actual != "04000000"+"0102e803"+"0d08" {
+		t.Fatalf("clvalue bytes error: %s", actual)
+	}
+	none := NewCLValue(NewNoneOption(NewCLType(TagU512)))
+	if actual := hex.EncodeToString(none.ToBytes()); actual != "01000000"+"00"+"0d08" {
+		t.Fatalf("clvalue bytes error: %s", actual)
+	}
+}
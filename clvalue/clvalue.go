@@ -1,5 +1,14 @@
 package clvalue
 
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
 const (
 	TagBool      = 0
 	TagI32       = 1
@@ -28,14 +37,14 @@ const (
 
 type CLValue struct {
 	value  CLTypedAndToBytes
-	clType int
+	clType *CLType
 	bytes  []byte
 }
 
-func NewCLValue(value CLTypedAndToBytes, clType int) *CLValue {
+func NewCLValue(value CLTypedAndToBytes) *CLValue {
 	return &CLValue{
 		value:  value,
-		clType: clType,
+		clType: TypeOf(value),
 		bytes:  value.ToBytes(),
 	}
 }
@@ -45,8 +54,119 @@ type CLTypedAndToBytes interface {
 	ToBytes() []byte
 }
 
-func clTypeEncoded() []byte {
-	//TODO
-	//return CLTypeHelper.toBytesHelper(c.clType())
+func (c *CLValue) Value() CLTypedAndToBytes {
+	return c.value
+}
+
+func (c *CLValue) CLType() *CLType {
+	return c.clType
+}
+
+func (c *CLValue) Bytes() []byte {
+	return c.bytes
+}
+
+// ToBytes serializes the value as: u32 length | value bytes | cl type bytes
+func (c *CLValue) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		ToBytesArrayU8(c.bytes),
+		c.clType.ToBytes(),
+	}, []byte{})
+}
+
+type clValueJson struct {
+	CLType *CLType     `json:"cl_type"`
+	Bytes  string      `json:"bytes"`
+	Parsed interface{} `json:"parsed"`
+}
+
+// MarshalJSON encodes the value as {"cl_type": ..., "bytes": "hex", "parsed": ...}
+func (c *CLValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(clValueJson{
+		CLType: c.clType,
+		Bytes:  hex.EncodeToString(c.bytes),
+		Parsed: parsedJson(c.value),
+	})
+}
+
+// UnmarshalJSON rebuilds the value from cl_type and bytes, parsed is ignored
+func (c *CLValue) UnmarshalJSON(data []byte) error {
+	var v clValueJson
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.CLType == nil {
+		return errors.New("clvalue cl_type is required")
+	}
+	b, err := hex.DecodeString(v.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid clvalue bytes: %v", err)
+	}
+	value, err := NewCLValueFromBytes(b, v.CLType)
+	if err != nil {
+		return err
+	}
+	*c = *value
+	return nil
+}
+
+// BigNumber is implemented by all the number cl values
+type BigNumber interface {
+	Value() *big.Int
+}
+
+// parsedJson returns the human readable form used by the node as "parsed"
+func parsedJson(value CLTypedAndToBytes) interface{} {
+	switch v := value.(type) {
+	case *Bool:
+		return v.Value()
+	case *I32, *I64, *U8, *U32, *U64:
+		return json.Number(v.(BigNumber).Value().String())
+	case *U128, *U256, *U512:
+		return v.(BigNumber).Value().String()
+	case *String:
+		return v.Value()
+	case *Key:
+		return map[string]string{keyNames[v.Variant()]: v.String()}
+	case *URef:
+		return v.String()
+	case *Option:
+		if !v.IsSome() {
+			return nil
+		}
+		return parsedJson(v.Value())
+	case *List:
+		list := make([]interface{}, 0, v.Len())
+		for _, e := range v.Value() {
+			list = append(list, parsedJson(e))
+		}
+		return list
+	case *Result:
+		if v.IsOk() {
+			return map[string]interface{}{"Ok": parsedJson(v.Value())}
+		}
+		return map[string]interface{}{"Err": parsedJson(v.Value())}
+	case *Map:
+		entries := make([]interface{}, 0, v.Len())
+		for _, e := range v.Value() {
+			entries = append(entries, map[string]interface{}{
+				"key":   parsedJson(e.Key),
+				"value": parsedJson(e.Value),
+			})
+		}
+		return entries
+	case *Tuple:
+		list := make([]interface{}, 0, len(v.Value()))
+		for _, e := range v.Value() {
+			list = append(list, parsedJson(e))
+		}
+		return list
+	case *ByteArray:
+		return hex.EncodeToString(v.Value())
+	case AccountHash:
+		return v.Hex()
+	case *PublicKey:
+		return v.String()
+	}
 	return nil
 }
@@ -0,0 +1,46 @@
+package clvalue
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func mustValue(v CLTypedAndToBytes, err error) CLTypedAndToBytes {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestPrimitive_ToBytes(t *testing.T) {
+	u128, _ := new(big.Int).SetString("123456789101112131415", 10)
+	cases := []struct {
+		value CLTypedAndToBytes
+		tag   int
+		bytes string
+	}{
+		{NewBool(true), TagBool, "01"},
+		{NewBool(false), TagBool, "00"},
+		{mustValue(NewI32(10)), TagI32, "0a000000"},
+		{mustValue(NewI64(1024)), TagI64, "0004000000000000"},
+		{mustValue(NewU8(7)), TagU8, "07"},
+		{mustValue(NewU32(17)), TagU32, "11000000"},
+		{mustValue(NewU64(1024)), TagU64, "0004000000000000"},
+		{mustValue(NewU64(math.MaxUint64)), TagU64, "ffffffffffffffff"},
+		{mustValue(NewU128(u128)), TagU128, "0957ff1ada959f4eb106"},
+		{mustValue(NewU256(big.NewInt(0))), TagU256, "00"},
+		{mustValue(NewU512(big.NewInt(1000))), TagU512, "02e803"},
+		{NewUnit(), TagUnit, ""},
+		{NewString("Hello, World!"), TagString, "0d00000048656c6c6f2c20576f726c6421"},
+	}
+	for _, c := range cases {
+		if c.value.GetCLType() != c.tag {
+			t.Errorf("cl type error, expect %d, actual %d", c.tag, c.value.GetCLType())
+		}
+		if actual := hex.EncodeToString(c.value.ToBytes()); actual != c.bytes {
+			t.Errorf("%s bytes error, expect %s, actual %s", TypeOf(c.value), c.bytes, actual)
+		}
+	}
+}
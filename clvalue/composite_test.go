@@ -0,0 +1,110 @@
+package clvalue
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestComposite_ToBytes(t *testing.T) {
+	u8, _ := NewU8(1)
+	list, err := NewList(NewCLType(TagString), NewString("a"), NewString("bc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMap(NewCLType(TagString), NewCLType(TagString))
+	if err = m.Set(NewString("a"), NewString("b")); err != nil {
+		t.Fatal(err)
+	}
+	key, _ := ParseKey("account-hash-" + strings.Repeat("45", 32))
+	cases := []struct {
+		value     CLTypedAndToBytes
+		bytes     string
+		typeBytes string
+	}{
+		{list, "02000000" + "0100000061" + "020000006263", "0e0a"},
+		{NewOkResult(u8, NewCLType(TagString)), "0101", "10030a"},
+		{NewErrResult(NewString("x"), NewCLType(TagU8)), "000100000078", "10030a"},
+		{m, "01000000" + "0100000061" + "0100000062", "110a0a"},
+		{NewTuple1(NewBool(true)), "01", "1200"},
+		{NewTuple2(u8, NewString("a")), "01" + "0100000061", "13030a"},
+		{NewTuple3(u8, u8, NewBool(false)), "010100", "14030300"},
+		{NewSomeOption(key), "01" + "00" + strings.Repeat("45", 32), "0d0b"},
+	}
+	for _, c := range cases {
+		clType := TypeOf(c.value)
+		if actual := hex.EncodeToString(c.value.ToBytes()); actual != c.bytes {
+			t.Errorf("%s bytes error, expect %s, actual %s", clType, c.bytes, actual)
+		}
+		if actual := hex.EncodeToString(clType.ToBytes()); actual != c.typeBytes {
+			t.Errorf("%s type bytes error, expect %s, actual %s", clType, c.typeBytes, actual)
+		}
+		decoded, err := CLValueFromBytes(NewCLValue(c.value).ToBytes())
+		if err != nil {
+			t.Fatalf("decode %s error: %v", clType, err)
+		}
+		if !decoded.CLType().Equal(clType) || !bytes.Equal(decoded.Value().ToBytes(), c.value.ToBytes()) {
+			t.Errorf("%s round trip error", clType)
+		}
+	}
+}
+
+func TestComposite_Errors(t *testing.T) {
+	if _, err := NewList(NewCLType(TagString), NewString("a"), NewBool(true)); err == nil {
+		t.Error("list element with wrong type should be rejected")
+	}
+	m := NewMap(NewCLType(TagString), NewCLType(TagU8))
+	u8, _ := NewU8(1)
+	if err := m.Set(NewString("a"), u8); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set(NewString("a"), u8); err == nil {
+		t.Error("duplicate map key should be rejected")
+	}
+	if err := m.Set(u8, u8); err == nil {
+		t.Error("map key with wrong type should be rejected")
+	}
+	mapType := NewMapType(NewCLType(TagU8), NewCLType(TagU8))
+	if _, err := FromBytes([]byte{2, 0, 0, 0, 1, 1, 1, 2}, mapType); err == nil {
+		t.Error("duplicate decoded map key should be rejected")
+	}
+	if _, err := FromBytes([]byte{2, 0, 0, 0, 1}, NewListType(NewCLType(TagU8))); err == nil {
+		t.Error("truncated list should be rejected")
+	}
+	if _, err := FromBytes([]byte{2, 1}, NewResultType(NewCLType(TagU8), NewCLType(TagU8))); err == nil {
+		t.Error("invalid result flag should be rejected")
+	}
+}
+
+func TestComposite_JSON(t *testing.T) {
+	list, _ := NewList(NewCLType(TagString), NewString("a"))
+	m := NewMap(NewCLType(TagString), NewCLType(TagString))
+	_ = m.Set(NewString("k"), NewString("v"))
+	cases := []struct {
+		value CLTypedAndToBytes
+		json  string
+	}{
+		{list, `{"cl_type":{"List":"String"},"bytes":"010000000100000061","parsed":["a"]}`},
+		{m, `{"cl_type":{"Map":{"key":"String","value":"String"}},"bytes":"01000000010000006b0100000076","parsed":[{"key":"k","value":"v"}]}`},
+		{NewErrResult(NewString("x"), NewCLType(TagUnit)), `{"cl_type":{"Result":{"err":"String","ok":"Unit"}},"bytes":"000100000078","parsed":{"Err":"x"}}`},
+		{NewTuple2(NewBool(true), NewString("a")), `{"cl_type":{"Tuple2":["Bool","String"]},"bytes":"010100000061","parsed":[true,"a"]}`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(NewCLValue(c.value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != c.json {
+			t.Errorf("json error,\nexpect: %s\nactual: %s", c.json, string(data))
+		}
+		var v CLValue
+		if err = json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+		if !v.CLType().Equal(TypeOf(c.value)) {
+			t.Errorf("json round trip type error: %s", v.CLType())
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package clvalue
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrUnsupportedCLType = errors.New("unsupported cl type")
+
+const (
+	// max nesting depth of a serialized cl type, the same as casper-types
+	maxCLTypeDepth = 50
+	// max number of elements that take no bytes in a value, e.g. the elements of List<Unit>,
+	// their count is not bounded by the input size
+	maxZeroWidthElements = 1024
+)
+
+// CLValueFromBytes decodes a serialized CLValue: u32 length | value bytes | cl type bytes
+func CLValueFromBytes(data []byte) (*CLValue, error) {
+	valueBytes, rest, err := readArrayU8(data)
+	if err != nil {
+		return nil, err
+	}
+	clType, rest, err := clTypeFromBytes(rest, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, ErrLeftOverBytes
+	}
+	return NewCLValueFromBytes(valueBytes, clType)
+}
+
+// NewCLValueFromBytes decodes the value bytes with the given type
+func NewCLValueFromBytes(valueBytes []byte, clType *CLType) (*CLValue, error) {
+	value, err := FromBytes(valueBytes, clType)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, len(valueBytes))
+	copy(b, valueBytes)
+	return &CLValue{
+		value:  value,
+		clType: clType,
+		bytes:  b,
+	}, nil
+}
+
+// CLTypeFromBytes decodes a serialized cl type, trailing bytes are rejected
+func CLTypeFromBytes(data []byte) (*CLType, error) {
+	t, rest, err := clTypeFromBytes(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, ErrLeftOverBytes
+	}
+	return t, nil
+}
+
+func clTypeFromBytes(data []byte, depth int) (*CLType, []byte, error) {
+	if depth >= maxCLTypeDepth {
+		return nil, nil, fmt.Errorf("%w: cl type is nested deeper than %d", ErrFormatting, maxCLTypeDepth)
+	}
+	tag, rest, err := readU8(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	t := NewCLType(int(tag))
+	if _, ok := clTypeNames[t.Tag]; !ok {
+		return nil, nil, fmt.Errorf("%w: unknown cl type tag %d", ErrFormatting, tag)
+	}
+	if t.Tag == TagByteArray {
+		t.Size, rest, err = readU32(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	for i := 0; i < innerTypeCount(t.Tag); i++ {
+		var inner *CLType
+		inner, rest, err = clTypeFromBytes(rest, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		t.Inner = append(t.Inner, inner)
+	}
+	return t, rest, nil
+}
+
+// FromBytes decodes the value bytes of the given type, trailing bytes are rejected
+func FromBytes(data []byte, clType *CLType) (CLTypedAndToBytes, error) {
+	zeroWidthLeft := maxZeroWidthElements
+	value, rest, err := fromBytes(data, clType, &zeroWidthLeft)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s error: %w", clType, err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("decode %s error: %w", clType, ErrLeftOverBytes)
+	}
+	return value, nil
+}
+
+func fromBytes(data []byte, t *CLType, zeroWidthLeft *int) (CLTypedAndToBytes, []byte, error) {
+	if err := checkInner(t); err != nil {
+		return nil, nil, err
+	}
+	switch t.Tag {
+	case TagBool:
+		b, rest, err := readU8(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if b > 1 {
+			return nil, nil, fmt.Errorf("%w: invalid bool %d", ErrFormatting, b)
+		}
+		return NewBool(b == 1), rest, nil
+	case TagI32:
+		v, rest, err := readU32(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		i32, err := NewI32(int32(v))
+		return i32, rest, err
+	case TagI64:
+		v, rest, err := readU64(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		i64, err := NewI64(int64(v))
+		return i64, rest, err
+	case TagU8:
+		v, rest, err := readU8(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		u8, err := NewU8(v)
+		return u8, rest, err
+	case TagU32:
+		v, rest, err := readU32(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		u32, err := NewU32(v)
+		return u32, rest, err
+	case TagU64:
+		v, rest, err := readU64(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		u64, err := NewU64(v)
+		return u64, rest, err
+	case TagU128, TagU256, TagU512:
+		return bigNumberFromBytes(data, t.Tag)
+	case TagUnit:
+		return NewUnit(), data, nil
+	case TagString:
+		s, rest, err := readString(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewString(s), rest, nil
+	case TagKey:
+		return keyFromBytes(data)
+	case TagURef:
+		return urefFromBytes(data)
+	case TagOption:
+		flag, rest, err := readU8(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch flag {
+		case 0:
+			return NewNoneOption(t.Inner[0]), rest, nil
+		case 1:
+			inner, rest, err := fromBytes(rest, t.Inner[0], zeroWidthLeft)
+			if err != nil {
+				return nil, nil, err
+			}
+			return &Option{inner: inner, innerType: t.Inner[0]}, rest, nil
+		default:
+			return nil, nil, fmt.Errorf("%w: invalid option flag %d", ErrFormatting, flag)
+		}
+	case TagList:
+		n, rest, err := readU32(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = checkCount(n, rest, minByteLen(t.Inner[0]), zeroWidthLeft); err != nil {
+			return nil, nil, err
+		}
+		l := &List{elemType: t.Inner[0], elements: []CLTypedAndToBytes{}}
+		for i := uint32(0); i < n; i++ {
+			var e CLTypedAndToBytes
+			e, rest, err = fromBytes(rest, t.Inner[0], zeroWidthLeft)
+			if err != nil {
+				return nil, nil, err
+			}
+			l.elements = append(l.elements, e)
+		}
+		return l, rest, nil
+	case TagResult:
+		flag, rest, err := readU8(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if flag != resultOkTag && flag != resultErrTag {
+			return nil, nil, fmt.Errorf("%w: invalid result flag %d", ErrFormatting, flag)
+		}
+		r := &Result{ok: flag == resultOkTag, okType: t.Inner[0], errType: t.Inner[1]}
+		valueType := r.errType
+		if r.ok {
+			valueType = r.okType
+		}
+		r.value, rest, err = fromBytes(rest, valueType, zeroWidthLeft)
+		if err != nil {
+			return nil, nil, err
+		}
+		return r, rest, nil
+	case TagMap:
+		n, rest, err := readU32(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = checkCount(n, rest, minByteLen(t.Inner[0])+minByteLen(t.Inner[1]), zeroWidthLeft); err != nil {
+			return nil, nil, err
+		}
+		m := NewMap(t.Inner[0], t.Inner[1])
+		for i := uint32(0); i < n; i++ {
+			var k, v CLTypedAndToBytes
+			k, rest, err = fromBytes(rest, t.Inner[0], zeroWidthLeft)
+			if err != nil {
+				return nil, nil, err
+			}
+			v, rest, err = fromBytes(rest, t.Inner[1], zeroWidthLeft)
+			if err != nil {
+				return nil, nil, err
+			}
+			if m.Get(k) != nil {
+				return nil, nil, fmt.Errorf("%w: duplicate map key", ErrFormatting)
+			}
+			m.entries = append(m.entries, &MapEntry{Key: k, Value: v})
+		}
+		return m, rest, nil
+	case TagTuple1, TagTuple2, TagTuple3:
+		tuple := &Tuple{}
+		rest := data
+		for _, inner := range t.Inner {
+			var e CLTypedAndToBytes
+			var err error
+			e, rest, err = fromBytes(rest, inner, zeroWidthLeft)
+			if err != nil {
+				return nil, nil, err
+			}
+			tuple.elements = append(tuple.elements, e)
+		}
+		return tuple, rest, nil
+	case TagByteArray:
+		b, rest, err := readBytes(data, int(t.Size))
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewByteArray(b), rest, nil
+	case TagAny:
+		// the length of Any is unknown, it takes all the remaining bytes
+		return NewAny(data), []byte{}, nil
+	case TagPublicKey:
+		return publicKeyFromBytes(data)
+	}
+	return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedCLType, t)
+}
+
+// checkCount rejects an element count that the remaining bytes can not hold,
+// the count is read from the input and must not drive the allocation
+func checkCount(n uint32, rest []byte, width int, zeroWidthLeft *int) error {
+	if width == 0 {
+		if uint64(n) > uint64(*zeroWidthLeft) {
+			return fmt.Errorf("%w: zero width elements exceed the limit %d", ErrFormatting, maxZeroWidthElements)
+		}
+		*zeroWidthLeft -= int(n)
+		return nil
+	}
+	if uint64(n) > uint64(len(rest)/width) {
+		return fmt.Errorf("%w: %d elements of at least %d bytes exceed the remaining %d bytes", ErrEarlyEndOfStream, n, width, len(rest))
+	}
+	return nil
+}
+
+// minByteLen returns the least number of bytes a value of the type takes
+func minByteLen(t *CLType) int {
+	switch t.Tag {
+	case TagBool, TagU8, TagU128, TagU256, TagU512, TagOption, TagResult:
+		return 1
+	case TagI32, TagU32, TagString, TagList, TagMap:
+		return 4
+	case TagI64, TagU64:
+		return 8
+	case TagKey:
+		// the era info key: tag | u64
+		return 9
+	case TagURef:
+		return 33
+	case TagPublicKey:
+		return 1 + publicKeyLen[Ed25519Tag]
+	case TagByteArray:
+		return int(t.Size)
+	case TagTuple1, TagTuple2, TagTuple3:
+		n := 0
+		for _, inner := range t.Inner {
+			if inner != nil {
+				n += minByteLen(inner)
+			}
+		}
+		return n
+	}
+	// Unit and Any
+	return 0
+}
+
+func innerTypeCount(tag int) int {
+	switch tag {
+	case TagOption, TagList, TagTuple1:
+		return 1
+	case TagResult, TagMap, TagTuple2:
+		return 2
+	case TagTuple3:
+		return 3
+	}
+	return 0
+}
+
+// checkInner checks the number of nested types
+func checkInner(t *CLType) error {
+	expect := innerTypeCount(t.Tag)
+	if len(t.Inner) != expect {
+		return fmt.Errorf("%w: %s requires %d inner types", ErrFormatting, clTypeNames[t.Tag], expect)
+	}
+	for _, inner := range t.Inner {
+		if inner == nil {
+			return fmt.Errorf("%w: nil inner type", ErrFormatting)
+		}
+	}
+	return nil
+}
+
+func bigNumberFromBytes(data []byte, tag int) (CLTypedAndToBytes, []byte, error) {
+	l, rest, err := readU8(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	bitSize := map[int]int{TagU128: 128, TagU256: 256, TagU512: 512}[tag]
+	if int(l) > bitSize/8 {
+		return nil, nil, fmt.Errorf("%w: invalid u%d length %d", ErrFormatting, bitSize, l)
+	}
+	b, rest, err := readBytes(rest, int(l))
+	if err != nil {
+		return nil, nil, err
+	}
+	be := make([]byte, len(b))
+	copy(be, b)
+	byteReverse(&be)
+	n := new(big.Int).SetBytes(be)
+	switch tag {
+	case TagU128:
+		v, err := NewU128(n)
+		return v, rest, err
+	case TagU256:
+		v, err := NewU256(n)
+		return v, rest, err
+	default:
+		v, err := NewU512(n)
+		return v, rest, err
+	}
+}
+
+func publicKeyFromBytes(data []byte) (*PublicKey, []byte, error) {
+	tag, rest, err := readU8(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	l, ok := publicKeyLen[tag]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unknown public key tag %d", ErrFormatting, tag)
+	}
+	raw, rest, err := readBytes(rest, l)
+	if err != nil {
+		return nil, nil, err
+	}
+	pk, err := NewPublicKey(tag, raw)
+	return pk, rest, err
+}
@@ -0,0 +1,146 @@
+package clvalue
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestFromBytes_RoundTrip(t *testing.T) {
+	u128, _ := new(big.Int).SetString("123456789101112131415", 10)
+	pk, _ := ParsePublicKey("0203447239548b66bdfe334131392dd9db386c054989e2b815fe68fd634c9e4703a1")
+	values := []CLTypedAndToBytes{
+		NewBool(true),
+		mustValue(NewI32(math.MinInt32)),
+		mustValue(NewI64(-1)),
+		mustValue(NewU8(255)),
+		mustValue(NewU32(17)),
+		mustValue(NewU64(math.MaxUint64)),
+		mustValue(NewU128(u128)),
+		mustValue(NewU256(big.NewInt(0))),
+		mustValue(NewU512(big.NewInt(2500000000))),
+		NewUnit(),
+		NewString("Hello, World!"),
+		NewSomeOption(NewString("abc")),
+		NewNoneOption(NewCLType(TagU64)),
+		NewByteArray(bytes.Repeat([]byte{1}, 32)),
+		pk,
+	}
+	for _, v := range values {
+		clValue, err := CLValueFromBytes(NewCLValue(v).ToBytes())
+		if err != nil {
+			t.Fatalf("decode %s error: %v", TypeOf(v), err)
+		}
+		if !clValue.CLType().Equal(TypeOf(v)) {
+			t.Errorf("decode type error, expect %s, actual %s", TypeOf(v), clValue.CLType())
+		}
+		if !bytes.Equal(clValue.Value().ToBytes(), v.ToBytes()) {
+			t.Errorf("decode %s value error", TypeOf(v))
+		}
+	}
+}
+
+func TestFromBytes_Typed(t *testing.T) {
+	v, err := FromBytes([]byte{0xf6, 0xff, 0xff, 0xff}, NewCLType(TagI32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(*I32).Value().Int64() != -10 {
+		t.Fatalf("i32 value error: %s", v.(*I32).Value())
+	}
+	data, _ := hex.DecodeString("0957ff1ada959f4eb106")
+	v, err = FromBytes(data, NewCLType(TagU512))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(*U512).Value().String() != "123456789101112131415" {
+		t.Fatalf("u512 value error: %s", v.(*U512).Value())
+	}
+}
+
+func TestFromBytes_Errors(t *testing.T) {
+	cases := []struct {
+		hex    string
+		clType *CLType
+		err    error
+	}{
+		{"", NewCLType(TagBool), ErrEarlyEndOfStream},
+		{"02", NewCLType(TagBool), ErrFormatting},
+		{"0100", NewCLType(TagBool), ErrLeftOverBytes},
+		{"0a0000", NewCLType(TagI32), ErrEarlyEndOfStream},
+		{"02e8", NewCLType(TagU512), ErrEarlyEndOfStream},
+		{"41" + "00", NewCLType(TagU512), ErrFormatting},
+		{"11" + "00", NewCLType(TagU128), ErrFormatting},
+		{"0d00000048656c6c6f", NewCLType(TagString), ErrEarlyEndOfStream},
+		{"02000000fffe", NewCLType(TagString), ErrFormatting},
+		{"02", NewOptionType(NewCLType(TagU8)), ErrFormatting},
+		{"0101", NewByteArrayType(32), ErrEarlyEndOfStream},
+		{"03", NewCLType(TagPublicKey), ErrFormatting},
+		{"00", &CLType{Tag: TagOption}, ErrFormatting},
+	}
+	for _, c := range cases {
+		data, _ := hex.DecodeString(c.hex)
+		_, err := FromBytes(data, c.clType)
+		if !errors.Is(err, c.err) {
+			t.Errorf("decode %s %s, expect error %v, actual %v", c.clType, c.hex, c.err, err)
+		}
+	}
+	if _, err := CLTypeFromBytes([]byte{0xff}); !errors.Is(err, ErrFormatting) {
+		t.Errorf("unknown tag should be rejected: %v", err)
+	}
+	if _, err := CLTypeFromBytes([]byte{TagOption}); !errors.Is(err, ErrEarlyEndOfStream) {
+		t.Errorf("truncated type should be rejected: %v", err)
+	}
+	if _, err := CLValueFromBytes([]byte{1, 0, 0, 0, 1, TagBool, 0}); !errors.Is(err, ErrLeftOverBytes) {
+		t.Errorf("trailing bytes should be rejected: %v", err)
+	}
+}
+
+// the element count is read from the input, it must not drive the allocation
+func TestFromBytes_Count(t *testing.T) {
+	unit := NewCLType(TagUnit)
+	cases := []struct {
+		name   string
+		data   []byte
+		clType *CLType
+	}{
+		{"List<Unit>", []byte{0xff, 0xff, 0xff, 0x0f}, NewListType(unit)},
+		{"List<(Unit,)>", []byte{0xff, 0xff, 0xff, 0xff}, NewListType(NewTuple1Type(NewTuple1Type(unit)))},
+		{"List<U64>", []byte{0xff, 0xff, 0xff, 0x0f, 0, 0, 0, 0, 0, 0, 0, 0}, NewListType(NewCLType(TagU64))},
+		{"List<ByteArray>", []byte{2, 0, 0, 0, 1, 2, 3}, NewListType(NewByteArrayType(4))},
+		{"Map<String,Unit>", []byte{0xff, 0xff, 0xff, 0x0f, 0, 0, 0, 0}, NewMapType(NewCLType(TagString), unit)},
+		// 2 * 1024 units in total
+		{"List<List<Unit>>", []byte{2, 0, 0, 0, 0, 4, 0, 0, 0, 4, 0, 0}, NewListType(NewListType(unit))},
+	}
+	for _, c := range cases {
+		start := time.Now()
+		if _, err := FromBytes(c.data, c.clType); err == nil {
+			t.Errorf("%s count should be rejected", c.name)
+		}
+		if time.Since(start) > time.Second {
+			t.Errorf("%s decode takes %v", c.name, time.Since(start))
+		}
+	}
+	v, err := FromBytes([]byte{3, 0, 0, 0}, NewListType(unit))
+	if err != nil || len(v.(*List).elements) != 3 {
+		t.Fatalf("small List<Unit> error: %v", err)
+	}
+}
+
+func TestCLTypeFromBytes_Depth(t *testing.T) {
+	nested := append(bytes.Repeat([]byte{TagList}, maxCLTypeDepth-1), TagUnit)
+	if _, err := CLTypeFromBytes(nested); err != nil {
+		t.Fatalf("depth %d should be accepted: %v", maxCLTypeDepth, err)
+	}
+	deep := append(bytes.Repeat([]byte{TagOption}, 100000), TagUnit)
+	if _, err := CLTypeFromBytes(deep); !errors.Is(err, ErrFormatting) {
+		t.Fatalf("deep type should be rejected: %v", err)
+	}
+	if _, err := CLValueFromBytes(append([]byte{0, 0, 0, 0}, deep...)); !errors.Is(err, ErrFormatting) {
+		t.Fatalf("deep value type should be rejected: %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package clvalue
+
+import "math/big"
+
+type I32 struct {
+	NumberCoder
+}
+
+func NewI32(value int32) (*I32, error) {
+	coder, err := NewNumberCoder(TagI32, 32, true, big.NewInt(int64(value)))
+	if err != nil {
+		return nil, err
+	}
+	return &I32{
+		NumberCoder: *coder,
+	}, nil
+}
@@ -0,0 +1,17 @@
+package clvalue
+
+import "math/big"
+
+type I64 struct {
+	NumberCoder
+}
+
+func NewI64(value int64) (*I64, error) {
+	coder, err := NewNumberCoder(TagI64, 64, true, big.NewInt(value))
+	if err != nil {
+		return nil, err
+	}
+	return &I64{
+		NumberCoder: *coder,
+	}, nil
+}
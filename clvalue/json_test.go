@@ -0,0 +1,73 @@
+package clvalue
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestCLValue_JSON(t *testing.T) {
+	u512, _ := NewU512(big.NewInt(2500000000))
+	u64, _ := NewU64(7)
+	pk, _ := ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	cases := []struct {
+		value CLTypedAndToBytes
+		json  string
+	}{
+		{u512, `{"cl_type":"U512","bytes":"0400f90295","parsed":"2500000000"}`},
+		{NewSomeOption(u64), `{"cl_type":{"Option":"U64"},"bytes":"010700000000000000","parsed":7}`},
+		{NewNoneOption(NewCLType(TagU64)), `{"cl_type":{"Option":"U64"},"bytes":"00","parsed":null}`},
+		{NewString("abc"), `{"cl_type":"String","bytes":"03000000616263","parsed":"abc"}`},
+		{NewBool(true), `{"cl_type":"Bool","bytes":"01","parsed":true}`},
+		{pk, `{"cl_type":"PublicKey","bytes":"0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c","parsed":"0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c"}`},
+		{NewByteArray([]byte{1, 2}), `{"cl_type":{"ByteArray":2},"bytes":"0102","parsed":"0102"}`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(NewCLValue(c.value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != c.json {
+			t.Errorf("json error,\nexpect: %s\nactual: %s", c.json, string(data))
+		}
+		var v CLValue
+		if err = json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+		again, _ := json.Marshal(&v)
+		if string(again) != c.json {
+			t.Errorf("round trip error,\nexpect: %s\nactual: %s", c.json, string(again))
+		}
+	}
+}
+
+func TestCLType_UnmarshalJSON(t *testing.T) {
+	cases := []string{
+		`"U512"`,
+		`{"Option":"U64"}`,
+		`{"List":{"Option":{"ByteArray":32}}}`,
+		`{"Result":{"err":"String","ok":"U8"}}`,
+		`{"Map":{"key":"String","value":"U512"}}`,
+		`{"Tuple3":["Key","URef","PublicKey"]}`,
+	}
+	for _, c := range cases {
+		var clType CLType
+		if err := json.Unmarshal([]byte(c), &clType); err != nil {
+			t.Fatalf("%s: %v", c, err)
+		}
+		data, _ := json.Marshal(&clType)
+		if string(data) != c {
+			t.Errorf("cl type round trip error, expect %s, actual %s", c, string(data))
+		}
+	}
+	for _, invalid := range []string{`"Foo"`, `"Option"`, `{"Option":"U64","List":"U8"}`, `{"Tuple2":["U8"]}`, `{"Map":{"key":"U8"}}`, `1`} {
+		var clType CLType
+		if err := json.Unmarshal([]byte(invalid), &clType); err == nil {
+			t.Errorf("%s should be rejected", invalid)
+		}
+	}
+	var v CLValue
+	if err := json.Unmarshal([]byte(`{"cl_type":"U64","bytes":"0700","parsed":7}`), &v); err == nil {
+		t.Error("truncated bytes should be rejected")
+	}
+}
@@ -0,0 +1,243 @@
+package clvalue
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type KeyVariant byte
+
+const (
+	KeyAccount KeyVariant = iota
+	KeyHash
+	KeyURef
+	KeyTransfer
+	KeyDeployInfo
+	KeyEraInfo
+	KeyBalance
+	KeyBid
+	KeyWithdraw
+	KeyDictionary
+)
+
+const keyHashLen = 32
+
+var keyPrefixes = map[KeyVariant]string{
+	KeyAccount:    "account-hash-",
+	KeyHash:       "hash-",
+	KeyURef:       urefPrefix,
+	KeyTransfer:   "transfer-",
+	KeyDeployInfo: "deploy-",
+	KeyEraInfo:    "era-",
+	KeyBalance:    "balance-",
+	KeyBid:        "bid-",
+	KeyWithdraw:   "withdraw-",
+	KeyDictionary: "dictionary-",
+}
+
+var keyNames = map[KeyVariant]string{
+	KeyAccount:    "Account",
+	KeyHash:       "Hash",
+	KeyURef:       "URef",
+	KeyTransfer:   "Transfer",
+	KeyDeployInfo: "DeployInfo",
+	KeyEraInfo:    "EraInfo",
+	KeyBalance:    "Balance",
+	KeyBid:        "Bid",
+	KeyWithdraw:   "Withdraw",
+	KeyDictionary: "Dictionary",
+}
+
+// Key is the address of a value in the global state
+type Key struct {
+	variant KeyVariant
+	// 32 bytes hash, unused by URef and EraInfo
+	hash  []byte
+	uref  *URef
+	eraId uint64
+}
+
+// NewKey builds the variants addressed by a 32 bytes hash,
+// use NewURefKey and NewEraInfoKey for the other variants
+func NewKey(variant KeyVariant, hash []byte) (*Key, error) {
+	if variant == KeyURef || variant == KeyEraInfo {
+		return nil, fmt.Errorf("key variant %s is not addressed by hash", keyNames[variant])
+	}
+	if _, ok := keyNames[variant]; !ok {
+		return nil, fmt.Errorf("unknown key variant: %d", variant)
+	}
+	if len(hash) != keyHashLen {
+		return nil, fmt.Errorf("invalid key hash len: %d", len(hash))
+	}
+	b := make([]byte, keyHashLen)
+	copy(b, hash)
+	return &Key{
+		variant: variant,
+		hash:    b,
+	}, nil
+}
+
+func NewAccountKey(accountHash []byte) (*Key, error) {
+	return NewKey(KeyAccount, accountHash)
+}
+
+func NewHashKey(hash []byte) (*Key, error) {
+	return NewKey(KeyHash, hash)
+}
+
+func NewURefKey(uref *URef) *Key {
+	return &Key{
+		variant: KeyURef,
+		uref:    uref,
+	}
+}
+
+func NewEraInfoKey(eraId uint64) *Key {
+	return &Key{
+		variant: KeyEraInfo,
+		eraId:   eraId,
+	}
+}
+
+// ParseKey parses the formatted string, e.g. account-hash-<64 hex>, hash-<64 hex>, uref-<64 hex>-007, era-10
+func ParseKey(s string) (*Key, error) {
+	// account-hash- must be checked before hash-
+	variants := []KeyVariant{KeyAccount, KeyURef, KeyTransfer, KeyDeployInfo, KeyEraInfo,
+		KeyBalance, KeyBid, KeyWithdraw, KeyDictionary, KeyHash}
+	for _, variant := range variants {
+		prefix := keyPrefixes[variant]
+		if !strings.HasPrefix(s, prefix) {
+			continue
+		}
+		switch variant {
+		case KeyURef:
+			uref, err := ParseURef(s)
+			if err != nil {
+				return nil, err
+			}
+			return NewURefKey(uref), nil
+		case KeyEraInfo:
+			eraId, err := strconv.ParseUint(strings.TrimPrefix(s, prefix), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid era id: %v", err)
+			}
+			return NewEraInfoKey(eraId), nil
+		default:
+			hash, err := hex.DecodeString(strings.TrimPrefix(s, prefix))
+			if err != nil {
+				return nil, fmt.Errorf("invalid key hash: %v", err)
+			}
+			return NewKey(variant, hash)
+		}
+	}
+	return nil, fmt.Errorf("unknown key prefix: %s", s)
+}
+
+func (k *Key) Variant() KeyVariant {
+	return k.variant
+}
+
+// Hash returns the 32 bytes hash, nil for URef and EraInfo
+func (k *Key) Hash() []byte {
+	return k.hash
+}
+
+func (k *Key) URef() *URef {
+	return k.uref
+}
+
+func (k *Key) EraId() uint64 {
+	return k.eraId
+}
+
+func (k *Key) GetCLType() int {
+	return TagKey
+}
+
+func (k *Key) ToBytes() []byte {
+	buf := []byte{byte(k.variant)}
+	switch k.variant {
+	case KeyURef:
+		return append(buf, k.uref.ToBytes()...)
+	case KeyEraInfo:
+		return append(buf, ToBytesU64(k.eraId)...)
+	}
+	return append(buf, k.hash...)
+}
+
+// String returns the formatted string
+func (k *Key) String() string {
+	switch k.variant {
+	case KeyURef:
+		return k.uref.String()
+	case KeyEraInfo:
+		return keyPrefixes[k.variant] + strconv.FormatUint(k.eraId, 10)
+	}
+	return keyPrefixes[k.variant] + hex.EncodeToString(k.hash)
+}
+
+func (k *Key) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	key, err := ParseKey(s)
+	if err != nil {
+		return err
+	}
+	*k = *key
+	return nil
+}
+
+func keyFromBytes(data []byte) (*Key, []byte, error) {
+	variant, rest, err := readU8(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch KeyVariant(variant) {
+	case KeyURef:
+		uref, rest, err := urefFromBytes(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewURefKey(uref), rest, nil
+	case KeyEraInfo:
+		eraId, rest, err := readU64(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewEraInfoKey(eraId), rest, nil
+	}
+	if _, ok := keyNames[KeyVariant(variant)]; !ok {
+		return nil, nil, fmt.Errorf("%w: unknown key variant %d", ErrFormatting, variant)
+	}
+	hash, rest, err := readBytes(rest, keyHashLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := NewKey(KeyVariant(variant), hash)
+	return key, rest, err
+}
+
+func urefFromBytes(data []byte) (*URef, []byte, error) {
+	addr, rest, err := readBytes(data, urefLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	rights, rest, err := readU8(rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	uref, err := NewURef(addr, AccessRights(rights))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrFormatting, err)
+	}
+	return uref, rest, nil
+}
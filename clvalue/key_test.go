@@ -0,0 +1,88 @@
+package clvalue
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	h := "45f3aa6ce2a450dd5a4f2cc4cc9054aded66de6b6cfc4ad977e7251cf94b649b"
+	cases := []struct {
+		key     string
+		variant KeyVariant
+		bytes   string
+	}{
+		{"account-hash-" + h, KeyAccount, "00" + h},
+		{"hash-" + h, KeyHash, "01" + h},
+		{"uref-" + h + "-007", KeyURef, "02" + h + "07"},
+		{"transfer-" + h, KeyTransfer, "03" + h},
+		{"deploy-" + h, KeyDeployInfo, "04" + h},
+		{"era-42", KeyEraInfo, "05" + "2a00000000000000"},
+		{"balance-" + h, KeyBalance, "06" + h},
+		{"bid-" + h, KeyBid, "07" + h},
+		{"withdraw-" + h, KeyWithdraw, "08" + h},
+		{"dictionary-" + h, KeyDictionary, "09" + h},
+	}
+	for _, c := range cases {
+		key, err := ParseKey(c.key)
+		if err != nil {
+			t.Fatalf("%s: %v", c.key, err)
+		}
+		if key.Variant() != c.variant {
+			t.Errorf("%s variant error: %d", c.key, key.Variant())
+		}
+		if key.String() != c.key {
+			t.Errorf("format error, expect %s, actual %s", c.key, key.String())
+		}
+		if actual := hex.EncodeToString(key.ToBytes()); actual != c.bytes {
+			t.Errorf("%s bytes error: %s", c.key, actual)
+		}
+		data, _ := hex.DecodeString(c.bytes)
+		decoded, err := FromBytes(data, NewCLType(TagKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.(*Key).String() != c.key {
+			t.Errorf("decode error, expect %s, actual %s", c.key, decoded.(*Key).String())
+		}
+	}
+	for _, invalid := range []string{"", "foo-" + h, "hash-" + h[:62], "era-x", "account-hash-zz", "uref-" + h + "-7", "uref-" + h + "-008"} {
+		if _, err := ParseKey(invalid); err == nil {
+			t.Errorf("%s should be rejected", invalid)
+		}
+	}
+}
+
+func TestURef(t *testing.T) {
+	s := "uref-" + strings.Repeat("2a", 32) + "-007"
+	uref, err := ParseURef(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uref.AccessRights() != AccessReadAddWrite {
+		t.Fatalf("access rights error: %d", uref.AccessRights())
+	}
+	if hex.EncodeToString(uref.ToBytes()) != strings.Repeat("2a", 32)+"07" {
+		t.Fatal("uref bytes error")
+	}
+	data, err := json.Marshal(NewCLValue(uref))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"cl_type":"URef","bytes":"` + strings.Repeat("2a", 32) + `07","parsed":"` + s + `"}`
+	if string(data) != expect {
+		t.Fatalf("uref json error: %s", string(data))
+	}
+	var v CLValue
+	if err = json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Value().(*URef).String() != s {
+		t.Fatal("uref round trip error")
+	}
+	if _, err = FromBytes(append(uref.ToBytes()[:32], 8), NewCLType(TagURef)); err == nil {
+		t.Fatal("invalid access rights should be rejected")
+	}
+}
@@ -0,0 +1,58 @@
+package clvalue
+
+import "fmt"
+
+// List is a variable length list, all elements have the same type
+type List struct {
+	elemType *CLType
+	elements []CLTypedAndToBytes
+}
+
+func NewList(elemType *CLType, elements ...CLTypedAndToBytes) (*List, error) {
+	l := &List{
+		elemType: elemType,
+		elements: []CLTypedAndToBytes{},
+	}
+	for _, e := range elements {
+		if err := l.Push(e); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+func (l *List) Push(element CLTypedAndToBytes) error {
+	if element == nil {
+		return fmt.Errorf("list element is nil")
+	}
+	if t := TypeOf(element); !t.Equal(l.elemType) {
+		return fmt.Errorf("list element type error, expect %s, actual %s", l.elemType, t)
+	}
+	l.elements = append(l.elements, element)
+	return nil
+}
+
+func (l *List) Value() []CLTypedAndToBytes {
+	return l.elements
+}
+
+func (l *List) Len() int {
+	return len(l.elements)
+}
+
+func (l *List) GetCLType() int {
+	return TagList
+}
+
+func (l *List) CLTypeDescriptor() *CLType {
+	return NewListType(l.elemType)
+}
+
+// ToBytes serializes the u32 length followed by the elements
+func (l *List) ToBytes() []byte {
+	buf := ToBytesU32(uint32(len(l.elements)))
+	for _, e := range l.elements {
+		buf = append(buf, e.ToBytes()...)
+	}
+	return buf
+}
@@ -0,0 +1,83 @@
+package clvalue
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type MapEntry struct {
+	Key   CLTypedAndToBytes
+	Value CLTypedAndToBytes
+}
+
+// Map keeps the entries in insertion order, keys must be unique
+type Map struct {
+	keyType   *CLType
+	valueType *CLType
+	entries   []*MapEntry
+}
+
+func NewMap(keyType, valueType *CLType) *Map {
+	return &Map{
+		keyType:   keyType,
+		valueType: valueType,
+		entries:   []*MapEntry{},
+	}
+}
+
+func (m *Map) Set(key, value CLTypedAndToBytes) error {
+	if key == nil || value == nil {
+		return fmt.Errorf("map key and value are required")
+	}
+	if t := TypeOf(key); !t.Equal(m.keyType) {
+		return fmt.Errorf("map key type error, expect %s, actual %s", m.keyType, t)
+	}
+	if t := TypeOf(value); !t.Equal(m.valueType) {
+		return fmt.Errorf("map value type error, expect %s, actual %s", m.valueType, t)
+	}
+	if m.Get(key) != nil {
+		return fmt.Errorf("duplicate map key: %x", key.ToBytes())
+	}
+	m.entries = append(m.entries, &MapEntry{
+		Key:   key,
+		Value: value,
+	})
+	return nil
+}
+
+// Get returns the value of the key, nil if not exists
+func (m *Map) Get(key CLTypedAndToBytes) CLTypedAndToBytes {
+	kb := key.ToBytes()
+	for _, e := range m.entries {
+		if bytes.Equal(e.Key.ToBytes(), kb) {
+			return e.Value
+		}
+	}
+	return nil
+}
+
+func (m *Map) Value() []*MapEntry {
+	return m.entries
+}
+
+func (m *Map) Len() int {
+	return len(m.entries)
+}
+
+func (m *Map) GetCLType() int {
+	return TagMap
+}
+
+func (m *Map) CLTypeDescriptor() *CLType {
+	return NewMapType(m.keyType, m.valueType)
+}
+
+// ToBytes serializes the u32 length followed by key, value pairs
+func (m *Map) ToBytes() []byte {
+	buf := ToBytesU32(uint32(len(m.entries)))
+	for _, e := range m.entries {
+		buf = append(buf, e.Key.ToBytes()...)
+		buf = append(buf, e.Value.ToBytes()...)
+	}
+	return buf
+}
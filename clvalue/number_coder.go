@@ -3,8 +3,12 @@ package clvalue
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"github.com/JFJun/casperlabs-go/common/hexutil"
+	"github.com/JFJun/casperlabs-go/common/numutil"
 	"math/big"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -81,47 +85,68 @@ func NewNumberCoder(clType int, bitSize uint32, signed bool, val interface{}) (*
 	if signed {
 		n = "i"
 	}
+	name := n + strconv.Itoa(int(bitSize))
+	if err := checkBounds(bigNum, bitSize, signed); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
 	return &NumberCoder{
 		clType:  clType,
 		bitSize: bitSize,
 		signed:  signed,
 		val:     bigNum,
-		name:    n + strconv.Itoa(int(bitSize)),
+		name:    name,
 	}, nil
 }
+
+// checkBounds checks the value fits in the integer type:
+// unsigned [0, 2^bitSize), signed [-2^(bitSize-1), 2^(bitSize-1))
+func checkBounds(val *big.Int, bitSize uint32, signed bool) error {
+	var min, max *big.Int
+	if signed {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(bitSize-1))
+		min = new(big.Int).Neg(max)
+	} else {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(bitSize))
+		min = big.NewInt(0)
+	}
+	if val.Cmp(min) < 0 {
+		return fmt.Errorf("value %s underflow, min is %s", val.String(), min.String())
+	}
+	if val.Cmp(max) >= 0 {
+		return fmt.Errorf("value %s overflow, max is %s", val.String(), new(big.Int).Sub(max, big.NewInt(1)).String())
+	}
+	return nil
+}
+
 func (nc *NumberCoder) GetCLType() int {
-	return 0
+	return nc.clType
+}
+
+func (nc *NumberCoder) Value() *big.Int {
+	return new(big.Int).Set(nc.val)
 }
 
 func (nc *NumberCoder) ToBytes() []byte {
-	vb := nc.val.Bytes()
-	if nc.val.Cmp(big.NewInt(0)) >= 0 {
-		// for positive number, we had to deal with paddings
-		if nc.bitSize > 64 {
-			// for u128, u256, u512, we have to and append extra byte for length
-			r := bytes.Join([][]byte{
-				vb,
-				{byte(len(vb))},
-			}, []byte{})
-			byteReverse(&r)
-			return r
-		} else {
-			// for other types, we have to add padding 0s
-			byteLen := nc.bitSize / 8
-			var b []byte
-			for i := 0; i < int(byteLen)-len(vb); i++ {
-				b = append(b, 0)
-			}
-			byteReverse(&vb)
-			r := bytes.Join([][]byte{
-				vb,
-				b[:],
-			}, []byte{})
-			return r
-		}
+	if nc.bitSize > 64 {
+		// for u128, u256, u512, the little endian bytes without padding are prefixed with the length byte
+		vb := nc.val.Bytes()
+		byteReverse(&vb)
+		return bytes.Join([][]byte{
+			{byte(len(vb))},
+			vb,
+		}, []byte{})
+	}
+	v := nc.val
+	if v.Sign() < 0 {
+		// negative numbers are written in two's complement
+		v = new(big.Int).Add(v, new(big.Int).Lsh(big.NewInt(1), uint(nc.bitSize)))
 	}
+	vb := v.Bytes()
 	byteReverse(&vb)
-	return vb
+	// for other types, we have to add padding 0s
+	b := make([]byte, nc.bitSize/8)
+	copy(b, vb)
+	return b
 }
 
 func byteReverse(s *[]byte) {
@@ -133,24 +158,57 @@ func byteReverse(s *[]byte) {
 func numberFrom(value interface{}) (*big.Int, error) {
 	switch v := value.(type) {
 	case big.Int:
-		return &v, nil
+		return new(big.Int).Set(&v), nil
 	case *big.Int:
-		return v, nil
-	//case uint, uint8, uint16, uint32, int:
-	//	return big.NewInt(int64(v)), nil
+		if v == nil {
+			return nil, errors.New("nil Number value")
+		}
+		return new(big.Int).Set(v), nil
+	case int:
+		return big.NewInt(int64(v)), nil
 	case int8:
 		return big.NewInt(int64(v)), nil
 	case int16:
 		return big.NewInt(int64(v)), nil
 	case int32:
 		return big.NewInt(int64(v)), nil
-	case uint32:
-		return big.NewInt(int64(v)), nil
-	case uint64:
-		i := int64(v)
-		return big.NewInt(i), nil
 	case int64:
 		return big.NewInt(v), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case string:
+		return numberFromString(v)
 	}
 	return nil, errors.New("invalid Number value")
 }
+
+// numberFromString accepts decimal strings with optional sign and 0x prefixed hex strings
+func numberFromString(s string) (*big.Int, error) {
+	neg := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	base := 10
+	if hexutil.Has0xPrefix(digits) {
+		digits = digits[2:]
+		base = 16
+	}
+	// big.Int.SetString accepts a sign, reject the sign after the 0x prefix, e.g. 0x-5
+	if digits == "" || strings.ContainsAny(digits[:1], "+-") || (base == 10 && !numutil.IsNum(digits)) {
+		return nil, fmt.Errorf("invalid Number string: %s", s)
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid Number string: %s", s)
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return n, nil
+}
@@ -1,7 +1,10 @@
 package clvalue
 
 import (
+	"encoding/hex"
 	"fmt"
+	"math"
+	"math/big"
 	"testing"
 )
 
@@ -14,3 +17,101 @@ func TestToBytes(t *testing.T) {
 	byteData := coder.ToBytes()
 	fmt.Println(byteData)
 }
+
+func TestNumberCoder_GetCLType(t *testing.T) {
+	u512, err := NewU512(big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u512.GetCLType() != TagU512 {
+		t.Fatalf("u512 cl type error: %d", u512.GetCLType())
+	}
+}
+
+func TestNumberCoder_Bounds(t *testing.T) {
+	maxU512 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 512), big.NewInt(1))
+	cases := []struct {
+		clType  int
+		bitSize uint32
+		signed  bool
+		val     interface{}
+		valid   bool
+	}{
+		{TagU8, 8, false, 255, true},
+		{TagU8, 8, false, 256, false},
+		{TagU8, 8, false, -1, false},
+		{TagU32, 32, false, uint32(math.MaxUint32), true},
+		{TagU64, 64, false, uint64(math.MaxUint64), true},
+		{TagU64, 64, false, "18446744073709551616", false},
+		{TagI32, 32, true, int32(math.MinInt32), true},
+		{TagI32, 32, true, int64(math.MaxInt32) + 1, false},
+		{TagI32, 32, true, int64(math.MinInt32) - 1, false},
+		{TagI64, 64, true, int64(math.MinInt64), true},
+		{TagU512, 512, false, maxU512, true},
+		{TagU512, 512, false, new(big.Int).Add(maxU512, big.NewInt(1)), false},
+		{TagU512, 512, false, big.NewInt(-1), false},
+	}
+	for _, c := range cases {
+		_, err := NewNumberCoder(c.clType, c.bitSize, c.signed, c.val)
+		if c.valid && err != nil {
+			t.Errorf("%v should be valid: %v", c.val, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%v should be rejected", c.val)
+		}
+	}
+}
+
+func TestNumberCoder_Negative(t *testing.T) {
+	cases := []struct {
+		bitSize uint32
+		val     int64
+		bytes   string
+	}{
+		{32, -10, "f6ffffff"},
+		{32, -1, "ffffffff"},
+		{32, math.MinInt32, "00000080"},
+		{64, -1024, "00fcffffffffffff"},
+		{64, math.MinInt64, "0000000000000080"},
+	}
+	for _, c := range cases {
+		coder, err := NewNumberCoder(TagI64, c.bitSize, true, c.val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := hex.EncodeToString(coder.ToBytes()); actual != c.bytes {
+			t.Errorf("%d bytes error, expect %s, actual %s", c.val, c.bytes, actual)
+		}
+	}
+}
+
+func TestNumberFrom(t *testing.T) {
+	cases := []struct {
+		val    interface{}
+		expect string
+	}{
+		{int(7), "7"},
+		{uint(7), "7"},
+		{uint8(255), "255"},
+		{uint16(65535), "65535"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{"123456789101112131415", "123456789101112131415"},
+		{"-42", "-42"},
+		{"0x3e8", "1000"},
+		{"-0x3e8", "-1000"},
+	}
+	for _, c := range cases {
+		n, err := numberFrom(c.val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.String() != c.expect {
+			t.Errorf("number from %v error: %s", c.val, n.String())
+		}
+	}
+	for _, invalid := range []interface{}{"", "12a", "0x", "0xzz", "0x-5", "0x+5", "--5", "-0x-5", 1.5, nil} {
+		if _, err := numberFrom(invalid); err == nil {
+			t.Errorf("%v should be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package clvalue
+
+type Option struct {
+	inner     CLTypedAndToBytes
+	innerType *CLType
+}
+
+func NewSomeOption(value CLTypedAndToBytes) *Option {
+	return &Option{
+		inner:     value,
+		innerType: TypeOf(value),
+	}
+}
+
+func NewNoneOption(innerType *CLType) *Option {
+	return &Option{
+		innerType: innerType,
+	}
+}
+
+func (o *Option) IsSome() bool {
+	return o.inner != nil
+}
+
+// Value returns the wrapped value, nil for None
+func (o *Option) Value() CLTypedAndToBytes {
+	return o.inner
+}
+
+func (o *Option) GetCLType() int {
+	return TagOption
+}
+
+func (o *Option) CLTypeDescriptor() *CLType {
+	return NewOptionType(o.innerType)
+}
+
+func (o *Option) ToBytes() []byte {
+	if o.inner == nil {
+		return []byte{0}
+	}
+	return append([]byte{1}, o.inner.ToBytes()...)
+}
@@ -0,0 +1,120 @@
+package clvalue
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/JFJun/casperlabs-go/common/hexutil"
+	"github.com/JFJun/casperlabs-go/keys/blake2b"
+)
+
+const (
+	Ed25519Tag   = 0x01
+	Secp256K1Tag = 0x02
+)
+
+var publicKeyLen = map[byte]int{
+	Ed25519Tag:   32,
+	Secp256K1Tag: 33,
+}
+
+var publicKeyAlgorithms = map[byte]string{
+	Ed25519Tag:   "ed25519",
+	Secp256K1Tag: "secp256k1",
+}
+
+// PublicKey holds the algorithm tag and the raw public key
+type PublicKey struct {
+	tag byte
+	raw []byte
+}
+
+func NewPublicKey(tag byte, raw []byte) (*PublicKey, error) {
+	l, ok := publicKeyLen[tag]
+	if !ok {
+		return nil, fmt.Errorf("unknown public key tag: %d", tag)
+	}
+	if len(raw) != l {
+		return nil, fmt.Errorf("invalid public key len,tag=%d,len=%d", tag, len(raw))
+	}
+	pk := make([]byte, l)
+	copy(pk, raw)
+	return &PublicKey{
+		tag: tag,
+		raw: pk,
+	}, nil
+}
+
+// ParsePublicKey parses the hex address, e.g. 01 + ed25519 public key
+func ParsePublicKey(address string) (*PublicKey, error) {
+	if hexutil.Has0xPrefix(address) {
+		address = address[2:]
+	}
+	data, err := hex.DecodeString(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty public key")
+	}
+	return NewPublicKey(data[0], data[1:])
+}
+
+func (p *PublicKey) Tag() byte {
+	return p.tag
+}
+
+func (p *PublicKey) Raw() []byte {
+	return p.raw
+}
+
+// Algorithm returns the signature algorithm name, ed25519 or secp256k1
+func (p *PublicKey) Algorithm() string {
+	return publicKeyAlgorithms[p.tag]
+}
+
+// AccountHash returns blake2b256(algorithm name | 0x00 | raw public key)
+func (p *PublicKey) AccountHash() AccountHash {
+	var ah AccountHash
+	copy(ah[:], blake2b.Hash(bytes.Join([][]byte{
+		[]byte(p.Algorithm()),
+		{0},
+		p.raw,
+	}, []byte{})))
+	return ah
+}
+
+func (p *PublicKey) Equal(other *PublicKey) bool {
+	return other != nil && p.tag == other.tag && bytes.Equal(p.raw, other.raw)
+}
+
+func (p *PublicKey) GetCLType() int {
+	return TagPublicKey
+}
+
+func (p *PublicKey) ToBytes() []byte {
+	return append([]byte{p.tag}, p.raw...)
+}
+
+// String returns the hex address
+func (p *PublicKey) String() string {
+	return hex.EncodeToString(p.ToBytes())
+}
+
+func (p *PublicKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *PublicKey) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	pk, err := ParsePublicKey(s)
+	if err != nil {
+		return err
+	}
+	*p = *pk
+	return nil
+}
@@ -0,0 +1,67 @@
+package clvalue
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"golang.org/x/crypto/blake2b"
+	"testing"
+)
+
+func TestParsePublicKey(t *testing.T) {
+	cases := []struct {
+		address   string
+		tag       byte
+		algorithm string
+	}{
+		{"0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c", Ed25519Tag, "ed25519"},
+		{"0203447239548b66bdfe334131392dd9db386c054989e2b815fe68fd634c9e4703a1", Secp256K1Tag, "secp256k1"},
+	}
+	for _, c := range cases {
+		pk, err := ParsePublicKey(c.address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pk.Tag() != c.tag || pk.Algorithm() != c.algorithm {
+			t.Errorf("%s algorithm error", c.address)
+		}
+		if pk.String() != c.address || hex.EncodeToString(pk.ToBytes()) != c.address {
+			t.Errorf("%s format error", c.address)
+		}
+		expect := blake2b.Sum256(append(append([]byte(c.algorithm), 0), pk.Raw()...))
+		accountHash := pk.AccountHash()
+		if accountHash != AccountHash(expect) {
+			t.Errorf("%s account hash error: %s", c.address, accountHash)
+		}
+		parsed, err := ParseAccountHash(accountHash.String())
+		if err != nil || parsed != accountHash {
+			t.Errorf("account hash round trip error: %v", err)
+		}
+		data, _ := json.Marshal(pk)
+		var decoded PublicKey
+		if err = json.Unmarshal(data, &decoded); err != nil || !decoded.Equal(pk) {
+			t.Errorf("public key json error: %v", err)
+		}
+	}
+	for _, invalid := range []string{"", "03447239", "0178a128", "0203447239548b66bdfe334131392dd9db386c054989e2b815fe68fd634c9e4703", "zz"} {
+		if _, err := ParsePublicKey(invalid); err == nil {
+			t.Errorf("%s should be rejected", invalid)
+		}
+	}
+}
+
+func TestAccountHash_CLValue(t *testing.T) {
+	ah, err := ParseAccountHash("account-hash-45f3aa6ce2a450dd5a4f2cc4cc9054aded66de6b6cfc4ad977e7251cf94b649b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := NewCLValue(ah)
+	if hex.EncodeToString(value.ToBytes()) != "20000000"+ah.Hex()+"0f20000000" {
+		t.Fatalf("account hash clvalue bytes error: %s", hex.EncodeToString(value.ToBytes()))
+	}
+	if ah.Key().String() != ah.String() {
+		t.Fatal("account hash key error")
+	}
+	if _, err = ParseAccountHash("account-hash-45f3"); err == nil {
+		t.Fatal("short account hash should be rejected")
+	}
+}
@@ -0,0 +1,56 @@
+package clvalue
+
+const (
+	resultErrTag = 0
+	resultOkTag  = 1
+)
+
+type Result struct {
+	ok      bool
+	value   CLTypedAndToBytes
+	okType  *CLType
+	errType *CLType
+}
+
+func NewOkResult(value CLTypedAndToBytes, errType *CLType) *Result {
+	return &Result{
+		ok:      true,
+		value:   value,
+		okType:  TypeOf(value),
+		errType: errType,
+	}
+}
+
+func NewErrResult(value CLTypedAndToBytes, okType *CLType) *Result {
+	return &Result{
+		ok:      false,
+		value:   value,
+		okType:  okType,
+		errType: TypeOf(value),
+	}
+}
+
+func (r *Result) IsOk() bool {
+	return r.ok
+}
+
+// Value returns the ok value or the err value
+func (r *Result) Value() CLTypedAndToBytes {
+	return r.value
+}
+
+func (r *Result) GetCLType() int {
+	return TagResult
+}
+
+func (r *Result) CLTypeDescriptor() *CLType {
+	return NewResultType(r.okType, r.errType)
+}
+
+func (r *Result) ToBytes() []byte {
+	tag := byte(resultErrTag)
+	if r.ok {
+		tag = resultOkTag
+	}
+	return append([]byte{tag}, r.value.ToBytes()...)
+}
@@ -0,0 +1,24 @@
+package clvalue
+
+type String struct {
+	value string
+}
+
+func NewString(value string) *String {
+	return &String{
+		value: value,
+	}
+}
+
+func (s *String) Value() string {
+	return s.value
+}
+
+func (s *String) GetCLType() int {
+	return TagString
+}
+
+// ToBytes serializes the utf-8 bytes prefixed with the u32 length
+func (s *String) ToBytes() []byte {
+	return ToBytesString(s.value)
+}
@@ -0,0 +1,51 @@
+package clvalue
+
+// Tuple is a Tuple1, Tuple2 or Tuple3 depending on the number of elements
+type Tuple struct {
+	elements []CLTypedAndToBytes
+}
+
+func NewTuple1(t1 CLTypedAndToBytes) *Tuple {
+	return &Tuple{
+		elements: []CLTypedAndToBytes{t1},
+	}
+}
+
+func NewTuple2(t1, t2 CLTypedAndToBytes) *Tuple {
+	return &Tuple{
+		elements: []CLTypedAndToBytes{t1, t2},
+	}
+}
+
+func NewTuple3(t1, t2, t3 CLTypedAndToBytes) *Tuple {
+	return &Tuple{
+		elements: []CLTypedAndToBytes{t1, t2, t3},
+	}
+}
+
+func (t *Tuple) Value() []CLTypedAndToBytes {
+	return t.elements
+}
+
+func (t *Tuple) GetCLType() int {
+	return TagTuple1 + len(t.elements) - 1
+}
+
+func (t *Tuple) CLTypeDescriptor() *CLType {
+	inner := make([]*CLType, 0, len(t.elements))
+	for _, e := range t.elements {
+		inner = append(inner, TypeOf(e))
+	}
+	return &CLType{
+		Tag:   t.GetCLType(),
+		Inner: inner,
+	}
+}
+
+func (t *Tuple) ToBytes() []byte {
+	var buf []byte
+	for _, e := range t.elements {
+		buf = append(buf, e.ToBytes()...)
+	}
+	return buf
+}
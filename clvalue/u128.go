@@ -0,0 +1,17 @@
+package clvalue
+
+import "math/big"
+
+type U128 struct {
+	NumberCoder
+}
+
+func NewU128(value *big.Int) (*U128, error) {
+	coder, err := NewNumberCoder(TagU128, 128, false, value)
+	if err != nil {
+		return nil, err
+	}
+	return &U128{
+		NumberCoder: *coder,
+	}, nil
+}
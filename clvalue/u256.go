@@ -0,0 +1,17 @@
+package clvalue
+
+import "math/big"
+
+type U256 struct {
+	NumberCoder
+}
+
+func NewU256(value *big.Int) (*U256, error) {
+	coder, err := NewNumberCoder(TagU256, 256, false, value)
+	if err != nil {
+		return nil, err
+	}
+	return &U256{
+		NumberCoder: *coder,
+	}, nil
+}
@@ -0,0 +1,17 @@
+package clvalue
+
+import "math/big"
+
+type U32 struct {
+	NumberCoder
+}
+
+func NewU32(value uint32) (*U32, error) {
+	coder, err := NewNumberCoder(TagU32, 32, false, new(big.Int).SetUint64(uint64(value)))
+	if err != nil {
+		return nil, err
+	}
+	return &U32{
+		NumberCoder: *coder,
+	}, nil
+}
@@ -0,0 +1,17 @@
+package clvalue
+
+import "math/big"
+
+type U64 struct {
+	NumberCoder
+}
+
+func NewU64(value uint64) (*U64, error) {
+	coder, err := NewNumberCoder(TagU64, 64, false, new(big.Int).SetUint64(value))
+	if err != nil {
+		return nil, err
+	}
+	return &U64{
+		NumberCoder: *coder,
+	}, nil
+}
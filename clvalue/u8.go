@@ -0,0 +1,17 @@
+package clvalue
+
+import "math/big"
+
+type U8 struct {
+	NumberCoder
+}
+
+func NewU8(value uint8) (*U8, error) {
+	coder, err := NewNumberCoder(TagU8, 8, false, new(big.Int).SetUint64(uint64(value)))
+	if err != nil {
+		return nil, err
+	}
+	return &U8{
+		NumberCoder: *coder,
+	}, nil
+}
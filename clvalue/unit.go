@@ -0,0 +1,16 @@
+package clvalue
+
+// Unit is the empty value, serialized as zero bytes
+type Unit struct{}
+
+func NewUnit() *Unit {
+	return &Unit{}
+}
+
+func (u *Unit) GetCLType() int {
+	return TagUnit
+}
+
+func (u *Unit) ToBytes() []byte {
+	return []byte{}
+}
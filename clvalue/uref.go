@@ -0,0 +1,106 @@
+package clvalue
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type AccessRights byte
+
+const (
+	AccessNone         AccessRights = 0
+	AccessRead         AccessRights = 1
+	AccessWrite        AccessRights = 2
+	AccessReadWrite    AccessRights = 3
+	AccessAdd          AccessRights = 4
+	AccessReadAdd      AccessRights = 5
+	AccessAddWrite     AccessRights = 6
+	AccessReadAddWrite AccessRights = 7
+)
+
+const (
+	urefPrefix = "uref-"
+	urefLen    = 32
+)
+
+// URef is an unforgeable reference: 32 bytes address and the access rights
+type URef struct {
+	addr         []byte
+	accessRights AccessRights
+}
+
+func NewURef(addr []byte, accessRights AccessRights) (*URef, error) {
+	if len(addr) != urefLen {
+		return nil, fmt.Errorf("invalid uref address len: %d", len(addr))
+	}
+	if accessRights > AccessReadAddWrite {
+		return nil, fmt.Errorf("invalid access rights: %d", accessRights)
+	}
+	b := make([]byte, urefLen)
+	copy(b, addr)
+	return &URef{
+		addr:         b,
+		accessRights: accessRights,
+	}, nil
+}
+
+// ParseURef parses the formatted string, e.g. uref-<64 hex>-007
+func ParseURef(s string) (*URef, error) {
+	if !strings.HasPrefix(s, urefPrefix) {
+		return nil, fmt.Errorf("invalid uref prefix: %s", s)
+	}
+	parts := strings.Split(strings.TrimPrefix(s, urefPrefix), "-")
+	if len(parts) != 2 || len(parts[1]) != 3 {
+		return nil, fmt.Errorf("invalid uref: %s", s)
+	}
+	addr, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid uref address: %v", err)
+	}
+	rights, err := strconv.ParseUint(parts[1], 8, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uref access rights: %v", err)
+	}
+	return NewURef(addr, AccessRights(rights))
+}
+
+func (u *URef) Addr() []byte {
+	return u.addr
+}
+
+func (u *URef) AccessRights() AccessRights {
+	return u.accessRights
+}
+
+func (u *URef) GetCLType() int {
+	return TagURef
+}
+
+func (u *URef) ToBytes() []byte {
+	return append(append([]byte{}, u.addr...), byte(u.accessRights))
+}
+
+// String returns the formatted string, e.g. uref-<64 hex>-007
+func (u *URef) String() string {
+	return fmt.Sprintf("%s%s-%03o", urefPrefix, hex.EncodeToString(u.addr), u.accessRights)
+}
+
+func (u *URef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+func (u *URef) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	uref, err := ParseURef(s)
+	if err != nil {
+		return err
+	}
+	*u = *uref
+	return nil
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -17,6 +18,9 @@ import (
 	"time"
 )
 
+//http请求的默认超时时间
+const DefaultTimeout = 30 * time.Second
+
 type Client struct {
 	client IRpcClient
 }
@@ -24,6 +28,17 @@ type IRpcClient interface {
 	SendRequest(method string, result interface{}, params interface{}) error
 }
 
+//支持ctx的IRpcClient,ctx的超时和取消会作用到http请求和websocket的读写
+type IRpcClientContext interface {
+	IRpcClient
+	SendRequestWithContext(ctx context.Context, method string, result interface{}, params interface{}) error
+}
+
+var (
+	_ IRpcClientContext = (*RpcClient)(nil)
+	_ IRpcClientContext = (*Socket)(nil)
+)
+
 func NewRpcClient(url, user, password string) (*Client, error) {
 	r := new(Client)
 	var ic IRpcClient
@@ -45,11 +60,23 @@ func (r *Client) Post(method string, result interface{}, params interface{}) err
 	return r.client.SendRequest(method, result, params)
 }
 
+//client没有实现IRpcClientContext时,只在请求前检查ctx
+func (r *Client) PostWithContext(ctx context.Context, method string, result interface{}, params interface{}) error {
+	if cc, ok := r.client.(IRpcClientContext); ok {
+		return cc.SendRequestWithContext(ctx, method, result, params)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.client.SendRequest(method, result, params)
+}
+
 //http
 type RpcClient struct {
 	rpcUrl      string
 	rpcUser     string
 	rpcPassword string
+	client      *http.Client
 }
 
 type RequestBody struct {
@@ -67,9 +94,9 @@ type RespBody struct {
 	Id      int         `json:"id"`
 }
 type RespErrorBody struct {
-	JsonRpc string                 `json:"jsonrpc"`
-	Error   map[string]interface{} `json:"error"`
-	Id      int                    `json:"id"`
+	JsonRpc string    `json:"jsonrpc"`
+	Error   *RPCError `json:"error"`
+	Id      int       `json:"id"`
 }
 
 //初始化一个rpc客户端
@@ -78,10 +105,25 @@ func Dial(url, user, password string) *RpcClient {
 		rpcUrl:      url,
 		rpcUser:     user,
 		rpcPassword: password,
+		client:      &http.Client{Timeout: DefaultTimeout},
 	}
 }
 
+/*
+设置每个请求的超时时间,d<=0时不设置超时,只受ctx控制
+*/
+func (rpc *RpcClient) SetTimeout(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	rpc.client = &http.Client{Timeout: d}
+}
+
 func (rpc *RpcClient) SendRequest(method string, result interface{}, params interface{}) error {
+	return rpc.SendRequestWithContext(context.Background(), method, result, params)
+}
+
+func (rpc *RpcClient) SendRequestWithContext(ctx context.Context, method string, result interface{}, params interface{}) error {
 	id := rand.Intn(10000)
 	var (
 		reqBytes []byte
@@ -108,7 +150,7 @@ func (rpc *RpcClient) SendRequest(method string, result interface{}, params inte
 	var (
 		req *http.Request
 	)
-	if req, err = http.NewRequest(http.MethodPost, rpc.rpcUrl, reqBuf); err != nil {
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, rpc.rpcUrl, reqBuf); err != nil {
 		return fmt.Errorf("http send error ,%v", err)
 	}
 	req.Header.Add("Content-Type", "application/json")
@@ -117,10 +159,13 @@ func (rpc *RpcClient) SendRequest(method string, result interface{}, params inte
 	if rpc.rpcUser != "" && rpc.rpcPassword != "" {
 		req.SetBasicAuth(rpc.rpcUser, rpc.rpcPassword)
 	}
-	client := &http.Client{}
+	client := rpc.client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
 	res, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("client do error, %v", err)
+		return fmt.Errorf("client do error, %w", err)
 	}
 	defer res.Body.Close()
 
@@ -134,6 +179,10 @@ func (rpc *RpcClient) SendRequest(method string, result interface{}, params inte
 		return fmt.Errorf("parse resp error,Err=【%v】", err)
 	}
 	if response.Result == nil {
+		var errBody RespErrorBody
+		if err := json.Unmarshal(resp, &errBody); err == nil && errBody.Error != nil {
+			return errBody.Error
+		}
 		return fmt.Errorf("unknown error, %s", string(resp))
 	}
 	data, err := json.Marshal(response.Result)
@@ -166,6 +215,7 @@ type Socket struct {
 	IsConnected       bool
 	sendMu            *sync.Mutex // Prevent "concurrent write to websocket connection"
 	receiveMu         *sync.Mutex
+	connMu            *sync.Mutex // 保护Conn的替换,读写都使用加锁取到的conn
 	reConnectNum      int
 }
 
@@ -187,6 +237,7 @@ func NewWebsocket(url string) Socket {
 		WebsocketDialer: &websocket.Dialer{},
 		sendMu:          &sync.Mutex{},
 		receiveMu:       &sync.Mutex{},
+		connMu:          &sync.Mutex{},
 	}
 }
 
@@ -198,10 +249,13 @@ func (socket *Socket) setConnectionOptions() {
 }
 
 func (socket *Socket) Connect() {
-	var err error
+	socket.connMu.Lock()
 	socket.setConnectionOptions()
-
-	socket.Conn, _, err = socket.WebsocketDialer.Dial(socket.Url, socket.RequestHeader)
+	conn, _, err := socket.WebsocketDialer.Dial(socket.Url, socket.RequestHeader)
+	if err == nil {
+		socket.Conn = conn
+	}
+	socket.connMu.Unlock()
 
 	if err != nil {
 		log.Println("Error while connecting to server ", err)
@@ -219,8 +273,8 @@ func (socket *Socket) Connect() {
 		socket.OnConnected(*socket)
 	}
 
-	defaultPingHandler := socket.Conn.PingHandler()
-	socket.Conn.SetPingHandler(func(appData string) error {
+	defaultPingHandler := conn.PingHandler()
+	conn.SetPingHandler(func(appData string) error {
 		log.Println("Received PING from server")
 		if socket.OnPingReceived != nil {
 			socket.OnPingReceived(appData, *socket)
@@ -228,8 +282,8 @@ func (socket *Socket) Connect() {
 		return defaultPingHandler(appData)
 	})
 
-	defaultPongHandler := socket.Conn.PongHandler()
-	socket.Conn.SetPongHandler(func(appData string) error {
+	defaultPongHandler := conn.PongHandler()
+	conn.SetPongHandler(func(appData string) error {
 		log.Println("Received PONG from server")
 		if socket.OnPongReceived != nil {
 			socket.OnPongReceived(appData, *socket)
@@ -237,8 +291,8 @@ func (socket *Socket) Connect() {
 		return defaultPongHandler(appData)
 	})
 
-	defaultCloseHandler := socket.Conn.CloseHandler()
-	socket.Conn.SetCloseHandler(func(code int, text string) error {
+	defaultCloseHandler := conn.CloseHandler()
+	conn.SetCloseHandler(func(code int, text string) error {
 		result := defaultCloseHandler(code, text)
 		log.Println("Disconnected from server ", result)
 		if socket.OnDisconnected != nil {
@@ -251,7 +305,7 @@ func (socket *Socket) Connect() {
 	go func() {
 		for {
 			socket.receiveMu.Lock()
-			messageType, message, err := socket.Conn.ReadMessage()
+			messageType, message, err := conn.ReadMessage()
 			socket.receiveMu.Unlock()
 			if err != nil {
 				log.Println("read:", err)
@@ -288,8 +342,12 @@ func (socket *Socket) SendBinary(data []byte) {
 }
 
 func (socket *Socket) send(messageType int, data []byte) error {
+	conn := socket.conn()
+	if conn == nil {
+		return errors.New("websocket is not connected")
+	}
 	socket.sendMu.Lock()
-	err := socket.Conn.WriteMessage(messageType, data)
+	err := conn.WriteMessage(messageType, data)
 	socket.sendMu.Unlock()
 	return err
 }
@@ -299,7 +357,9 @@ func (socket *Socket) Close() {
 	if err != nil {
 		log.Println("write close:", err)
 	}
-	socket.Conn.Close()
+	if conn := socket.conn(); conn != nil {
+		socket.dropConn(conn)
+	}
 	if socket.OnDisconnected != nil {
 		socket.IsConnected = false
 		socket.OnDisconnected(err, *socket)
@@ -311,11 +371,22 @@ type JsonRpcResponse struct {
 	Method  string      `json:"method"`
 	Id      int         `json:"id"`
 	Result  interface{} `json:"result"`
-	Error   interface{} `json:"error"`
+	Error   *RPCError   `json:"error"`
 }
 
 func (socket *Socket) SendRequest(method string, result interface{}, params interface{}) error {
-	err := socket.reConnect()
+	return socket.SendRequestWithContext(context.Background(), method, result, params)
+}
+
+/*
+ctx的deadline会设置为连接的读写deadline,ctx被取消时会中断正在进行的读
+请求结束后恢复为不超时
+*/
+func (socket *Socket) SendRequestWithContext(ctx context.Context, method string, result interface{}, params interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	conn, err := socket.reConnect(ctx)
 	if err != nil {
 		return err
 	}
@@ -326,17 +397,40 @@ func (socket *Socket) SendRequest(method string, result interface{}, params inte
 		"params":  params,
 	}
 	dd, _ := json.Marshal(reqData)
+	deadline, _ := ctx.Deadline()
 	socket.sendMu.Lock()
-	err = socket.Conn.WriteMessage(websocket.BinaryMessage, dd)
+	conn.SetWriteDeadline(deadline)
+	err = conn.WriteMessage(websocket.BinaryMessage, dd)
+	conn.SetWriteDeadline(time.Time{})
 	socket.sendMu.Unlock()
 	if err != nil {
-		return fmt.Errorf("ws send req data error,Err=%v", err)
+		//写失败后连接不可再用,下次请求时重新连接
+		socket.dropConn(conn)
+		return fmt.Errorf("ws send req data error,Err=%w", ctxError(ctx, err))
 	}
 	socket.receiveMu.Lock()
-	_, respData, err := socket.Conn.ReadMessage()
+	conn.SetReadDeadline(deadline)
+	done, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			//让正在进行的ReadMessage立即返回
+			conn.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	_, respData, err := conn.ReadMessage()
+	close(done)
+	<-exited
+	conn.SetReadDeadline(time.Time{})
 	socket.receiveMu.Unlock()
 	if err != nil {
-		return fmt.Errorf("ws resp data error,Err=%v", err)
+		//读超时后连接不可再用,下次请求时重新连接
+		socket.dropConn(conn)
+	}
+	if err != nil {
+		return fmt.Errorf("ws resp data error,Err=%w", ctxError(ctx, err))
 	}
 	if len(respData) == 0 {
 		return errors.New("ws resp data is null")
@@ -347,8 +441,7 @@ func (socket *Socket) SendRequest(method string, result interface{}, params inte
 		return fmt.Errorf("ws json unmarshal resp data error,err=%v", err)
 	}
 	if resp.Error != nil {
-		errData, _ := json.Marshal(resp.Error)
-		return fmt.Errorf("ws resp error ,err=%s", string(errData))
+		return resp.Error
 	}
 	if resp.Result == nil {
 		return errors.New("ws resp result is null")
@@ -361,12 +454,22 @@ func (socket *Socket) SendRequest(method string, result interface{}, params inte
 	return nil
 }
 
-func (socket *Socket) reConnect() error {
-	if socket.Conn == nil {
-		var err error
+//ctx已经结束时返回ctx.Err(),方便调用方用errors.Is判断
+func ctxError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+//ctx作用于websocket握手,返回当前可用的连接
+func (socket *Socket) reConnect(ctx context.Context) (*websocket.Conn, error) {
+	socket.connMu.Lock()
+	defer socket.connMu.Unlock()
+	for socket.Conn == nil {
 		socket.setConnectionOptions()
 
-		socket.Conn, _, err = socket.WebsocketDialer.Dial(socket.Url, socket.RequestHeader)
+		conn, _, err := socket.WebsocketDialer.DialContext(ctx, socket.Url, socket.RequestHeader)
 
 		if err != nil {
 			log.Printf("error while connecting to server,err=%v,reConnect num is %d ", err, socket.reConnectNum)
@@ -375,13 +478,32 @@ func (socket *Socket) reConnect() error {
 			if socket.OnConnectError != nil {
 				socket.OnConnectError(err, *socket)
 			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			if socket.reConnectNum >= 3 {
-				return err
+				return nil, err
 			}
-			return socket.reConnect()
+			continue
 		}
+		socket.Conn = conn
 		socket.reConnectNum = 0
 	}
+	return socket.Conn, nil
+}
 
-	return nil
+func (socket *Socket) conn() *websocket.Conn {
+	socket.connMu.Lock()
+	defer socket.connMu.Unlock()
+	return socket.Conn
+}
+
+//关闭conn,如果它仍是当前连接则置空,已经被替换的连接不影响新连接
+func (socket *Socket) dropConn(conn *websocket.Conn) {
+	socket.connMu.Lock()
+	if socket.Conn == conn {
+		socket.Conn = nil
+	}
+	socket.connMu.Unlock()
+	conn.Close()
 }
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 节点返回的错误,用于errors.Is判断错误类型
+var (
+	ErrNoSuchDeploy      = errors.New("no such deploy")
+	ErrNoSuchBlock       = errors.New("no such block")
+	ErrStateRootNotFound = errors.New("state root not found")
+	ErrInvalidDeploy     = errors.New("invalid deploy")
+)
+
+/*
+根据错误信息判断错误类型,错误码在不同版本的节点中含义不同,
+例如1.0-1.3版本中-32000是通用的server error,不能只根据错误码判断
+*/
+var rpcErrorMatchers = []struct {
+	err      error
+	messages []string
+}{
+	{ErrNoSuchDeploy, []string{"no such deploy", "deploy not known"}},
+	{ErrNoSuchBlock, []string{"no such block", "block not known"}},
+	{ErrInvalidDeploy, []string{"invalid deploy"}},
+	{ErrStateRootNotFound, []string{"state root not found", "state root hash not found", "no such state root", "rootnotfound"}},
+}
+
+/*
+json-rpc返回的error字段,可以用errors.As获取:
+
+	var rpcErr *common.RPCError
+	if errors.As(err, &rpcErr) {
+		fmt.Println(rpcErr.Code, rpcErr.Message)
+	}
+*/
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	if len(e.Data) != 0 {
+		return fmt.Sprintf("rpc error %d: %s, data=%s", e.Code, e.Message, string(e.Data))
+	}
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+/*
+根据错误信息判断是否为ErrNoSuchDeploy,ErrNoSuchBlock,ErrStateRootNotFound,ErrInvalidDeploy
+*/
+func (e *RPCError) Is(target error) bool {
+	msg := strings.ToLower(e.Message)
+	for _, m := range rpcErrorMatchers {
+		if m.err != target {
+			continue
+		}
+		for _, s := range m.messages {
+			if strings.Contains(msg, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRPCError_Is(t *testing.T) {
+	testCases := []struct {
+		err    *RPCError
+		target error
+		is     bool
+	}{
+		{&RPCError{Code: -32000, Message: "deploy not known"}, ErrNoSuchDeploy, true},
+		{&RPCError{Code: -32600, Message: "No such deploy"}, ErrNoSuchDeploy, true},
+		{&RPCError{Code: -32001, Message: "block not known"}, ErrNoSuchBlock, true},
+		{&RPCError{Code: -32001, Message: "block not known"}, ErrNoSuchDeploy, false},
+		{&RPCError{Code: -32003, Message: "state query failed: state root hash not found"}, ErrStateRootNotFound, true},
+		{&RPCError{Code: -32008, Message: "invalid deploy: the deploy is expired"}, ErrInvalidDeploy, true},
+		{&RPCError{Code: -32602, Message: "Invalid params"}, ErrInvalidDeploy, false},
+		// -32000 is the generic server error of 1.0-1.3 nodes
+		{&RPCError{Code: -32000, Message: "state query failed"}, ErrNoSuchDeploy, false},
+		{&RPCError{Code: -32001, Message: "get balance failed"}, ErrNoSuchBlock, false},
+		{&RPCError{Code: -32008, Message: "purse not found"}, ErrInvalidDeploy, false},
+	}
+	for _, tc := range testCases {
+		if errors.Is(tc.err, tc.target) != tc.is {
+			t.Fatalf("%v is %v, expect %v", tc.err, tc.target, tc.is)
+		}
+	}
+}
+
+func TestRpcClient_RPCError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32008,"message":"invalid deploy","data":"the approval at index 0 is invalid"}}`))
+	}))
+	defer server.Close()
+	var res interface{}
+	err := Dial(server.URL, "", "").SendRequest("account_put_deploy", &res, nil)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32008 || string(rpcErr.Data) != `"the approval at index 0 is invalid"` {
+		t.Fatalf("rpc error: %v", err)
+	}
+	if !errors.Is(err, ErrInvalidDeploy) || errors.Is(err, ErrNoSuchDeploy) {
+		t.Fatalf("rpc error is: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"github.com/gorilla/websocket"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSocket_SendRequestWithContext(t *testing.T) {
+	var requests int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+			//第一个请求不返回,之后的请求正常返回
+			if atomic.AddInt32(&requests, 1) > 1 {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"result":{"api_version":"1.0.0"}}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	socket := NewWebsocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	var res struct {
+		ApiVersion string `json:"api_version"`
+	}
+	if err := socket.SendRequestWithContext(ctx, "info_get_status", &res, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancel error: %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := socket.SendRequestWithContext(ctx, "info_get_status", &res, nil); err != nil {
+		t.Fatal(err)
+	}
+	if res.ApiVersion != "1.0.0" {
+		t.Fatalf("result error: %+v", res)
+	}
+}
+
+func TestSocket_SendRequestWithContext_Parallel(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			//slow请求不返回,让它超时
+			if strings.Contains(string(data), `"slow"`) {
+				continue
+			}
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"result":{"api_version":"1.0.0"}}`))
+		}
+	}))
+	defer server.Close()
+
+	socket := NewWebsocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	var (
+		wg     sync.WaitGroup
+		failed int32
+	)
+	for i := 0; i < 8; i++ {
+		method, timeout := "info_get_status", 500*time.Millisecond
+		if i == 0 {
+			method, timeout = "slow", 50*time.Millisecond
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			var res interface{}
+			if err := socket.SendRequestWithContext(ctx, method, &res, nil); err != nil {
+				atomic.AddInt32(&failed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	//少返回了一个响应,至少有一个请求失败
+	if atomic.LoadInt32(&failed) == 0 {
+		t.Fatal("expect at least one request to time out")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var res struct {
+		ApiVersion string `json:"api_version"`
+	}
+	if err := socket.SendRequestWithContext(ctx, "info_get_status", &res, nil); err != nil {
+		t.Fatal(err)
+	}
+	if res.ApiVersion != "1.0.0" {
+		t.Fatalf("result error: %+v", res)
+	}
+}
+
+func TestSocket_HandshakeContext(t *testing.T) {
+	// accept the connection but never answer the handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	socket := NewWebsocket("ws://" + listener.Addr().String())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	var res interface{}
+	if err = socket.SendRequestWithContext(ctx, "info_get_status", &res, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("handshake error: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("handshake is not cancelled: %v", time.Since(start))
+	}
+}
+
+type plainRpcClient struct {
+	calls int
+}
+
+func (p *plainRpcClient) SendRequest(method string, result interface{}, params interface{}) error {
+	p.calls++
+	return nil
+}
+
+func TestClient_PostWithContext_PlainClient(t *testing.T) {
+	plain := &plainRpcClient{}
+	c := &Client{client: plain}
+	if err := c.PostWithContext(context.Background(), "info_get_status", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.PostWithContext(ctx, "info_get_status", nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context.Canceled, got %v", err)
+	}
+	if plain.calls != 1 {
+		t.Fatalf("expect 1 call, got %d", plain.calls)
+	}
+}
@@ -0,0 +1,551 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+/*
+RuntimeArgsFrom builds the runtime args from the tagged fields of a struct, e.g.
+
+	type CEP18Transfer struct {
+		Recipient string   `casper:"recipient,key"`
+		Amount    *big.Int `casper:"amount,u256"`
+	}
+
+the tag is `casper:"name[,type][,option]"`, fields without tag are ignored.
+Without type the cl type is inferred from the go type:
+
+	bool -> Bool, int32 -> I32, int/int64 -> I64, uint8 -> U8, uint32 -> U32, uint/uint64 -> U64,
+	*big.Int/big.Int -> U512, string -> String, [N]byte -> ByteArray(N),
+	slice -> List, map -> Map (entries sorted by key bytes), pointer -> Option,
+	values implementing cl.CLTypedAndToBytes are used as they are.
+
+Supported types: bool, i32, i64, u8, u32, u64, u128, u256, u512, string, unit,
+key, uref, public_key (parsed from string or the cl type) and byte_array.
+The option flag wraps the value in an Option, nil is None.
+*/
+func RuntimeArgsFrom(v interface{}) (RuntimeArgs, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return RuntimeArgs{}, errors.New("runtime args source is nil")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return RuntimeArgs{}, fmt.Errorf("runtime args source must be a struct, got %s", rv.Kind())
+	}
+	fields, err := argFields(rv.Type())
+	if err != nil {
+		return RuntimeArgs{}, err
+	}
+	var ra RuntimeArgs
+	for _, f := range fields {
+		value, err := clValueOf(rv.Field(f.index), f.spec)
+		if err != nil {
+			return RuntimeArgs{}, fmt.Errorf("arg %s: %v", f.name, err)
+		}
+		if err := ra.Insert(f.name, value); err != nil {
+			return RuntimeArgs{}, err
+		}
+	}
+	return ra, nil
+}
+
+// Unmarshal stores the args into the tagged fields of the struct pointed by v,
+// see RuntimeArgsFrom for the tag format. Missing args are only allowed for option fields.
+func (ra RuntimeArgs) Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("unmarshal target must be a non-nil struct pointer")
+	}
+	rv = rv.Elem()
+	fields, err := argFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		arg := ra.Get(f.name)
+		if arg == nil {
+			if f.spec.option || rv.Field(f.index).Kind() == reflect.Ptr {
+				continue
+			}
+			return fmt.Errorf("arg %s not found", f.name)
+		}
+		if err := setGoValue(rv.Field(f.index), arg.Value(), f.spec); err != nil {
+			return fmt.Errorf("arg %s: %v", f.name, err)
+		}
+	}
+	return nil
+}
+
+type argSpec struct {
+	clType string
+	option bool
+}
+
+type argField struct {
+	index int
+	name  string
+	spec  argSpec
+}
+
+func argFields(rt reflect.Type) ([]argField, error) {
+	var fields []argField
+	names := map[string]bool{}
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag, ok := sf.Tag.Lookup("casper")
+		if !ok || tag == "-" {
+			continue
+		}
+		if sf.PkgPath != "" {
+			return nil, fmt.Errorf("field %s is unexported", sf.Name)
+		}
+		parts := strings.Split(tag, ",")
+		f := argField{index: i, name: parts[0]}
+		if f.name == "" {
+			return nil, fmt.Errorf("field %s: arg name is required", sf.Name)
+		}
+		for _, p := range parts[1:] {
+			if p == "option" {
+				f.spec.option = true
+			} else if p != "" {
+				f.spec.clType = p
+			}
+		}
+		if names[f.name] {
+			return nil, fmt.Errorf("duplicate arg name: %s", f.name)
+		}
+		names[f.name] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+var (
+	bigIntType = reflect.TypeOf(big.Int{})
+	clValType  = reflect.TypeOf((*cl.CLTypedAndToBytes)(nil)).Elem()
+)
+
+func clValueOf(rv reflect.Value, spec argSpec) (cl.CLTypedAndToBytes, error) {
+	if spec.option {
+		inner := argSpec{clType: spec.clType}
+		if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+			t := rv.Type()
+			if isOptionPtr(t) {
+				t = t.Elem()
+			}
+			innerType, err := clTypeOf(t, inner)
+			if err != nil {
+				return nil, err
+			}
+			return cl.NewNoneOption(innerType), nil
+		}
+		if isOptionPtr(rv.Type()) {
+			rv = rv.Elem()
+		}
+		value, err := clValueOf(rv, inner)
+		if err != nil {
+			return nil, err
+		}
+		return cl.NewSomeOption(value), nil
+	}
+	if rv.Type().Implements(clValType) {
+		if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+			return nil, errors.New("value is nil")
+		}
+		return rv.Interface().(cl.CLTypedAndToBytes), nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.Type().Elem() == bigIntType {
+			if rv.IsNil() {
+				return nil, errors.New("value is nil")
+			}
+			return numberOf(rv.Interface().(*big.Int), spec.clType)
+		}
+		if rv.IsNil() {
+			t, err := clTypeOf(rv.Type().Elem(), spec)
+			if err != nil {
+				return nil, err
+			}
+			return cl.NewNoneOption(t), nil
+		}
+		value, err := clValueOf(rv.Elem(), spec)
+		if err != nil {
+			return nil, err
+		}
+		return cl.NewSomeOption(value), nil
+	case reflect.Struct:
+		if rv.Type() == bigIntType {
+			n := rv.Interface().(big.Int)
+			return numberOf(&n, spec.clType)
+		}
+	case reflect.Bool:
+		if spec.clType != "" && spec.clType != "bool" {
+			return nil, fmt.Errorf("can not encode bool as %s", spec.clType)
+		}
+		return cl.NewBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return numberOf(big.NewInt(rv.Int()), defaultSpec(spec.clType, rv.Kind()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return numberOf(new(big.Int).SetUint64(rv.Uint()), defaultSpec(spec.clType, rv.Kind()))
+	case reflect.String:
+		return stringValueOf(rv.String(), spec.clType)
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 && (spec.clType == "" || spec.clType == "byte_array") {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return cl.NewByteArray(b), nil
+		}
+		return listValueOf(rv, spec)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 && spec.clType == "byte_array" {
+			return cl.NewByteArray(rv.Bytes()), nil
+		}
+		return listValueOf(rv, spec)
+	case reflect.Map:
+		return mapValueOf(rv)
+	}
+	return nil, fmt.Errorf("unsupported go type %s", rv.Type())
+}
+
+// isOptionPtr reports whether the pointer type maps to an Option,
+// *big.Int and the cl values are pointers but not options
+func isOptionPtr(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Ptr && rt.Elem() != bigIntType && !rt.Implements(clValType)
+}
+
+// defaultSpec returns the cl type of go integer kinds
+func defaultSpec(clType string, kind reflect.Kind) string {
+	if clType != "" {
+		return clType
+	}
+	switch kind {
+	case reflect.Int, reflect.Int64:
+		return "i64"
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return "i32"
+	case reflect.Uint8:
+		return "u8"
+	case reflect.Uint16, reflect.Uint32:
+		return "u32"
+	}
+	return "u64"
+}
+
+func numberOf(n *big.Int, clType string) (cl.CLTypedAndToBytes, error) {
+	// the fixed size integers are built with their own types, NumberCoder is only used for the range check
+	switch clType {
+	case "i32":
+		if _, err := cl.NewNumberCoder(cl.TagI32, 32, true, n); err != nil {
+			return nil, err
+		}
+		return cl.NewI32(int32(n.Int64()))
+	case "i64":
+		if _, err := cl.NewNumberCoder(cl.TagI64, 64, true, n); err != nil {
+			return nil, err
+		}
+		return cl.NewI64(n.Int64())
+	case "u8":
+		if _, err := cl.NewNumberCoder(cl.TagU8, 8, false, n); err != nil {
+			return nil, err
+		}
+		return cl.NewU8(uint8(n.Uint64()))
+	case "u32":
+		if _, err := cl.NewNumberCoder(cl.TagU32, 32, false, n); err != nil {
+			return nil, err
+		}
+		return cl.NewU32(uint32(n.Uint64()))
+	case "u64":
+		if _, err := cl.NewNumberCoder(cl.TagU64, 64, false, n); err != nil {
+			return nil, err
+		}
+		return cl.NewU64(n.Uint64())
+	case "u128":
+		return cl.NewU128(n)
+	case "u256":
+		return cl.NewU256(n)
+	case "", "u512":
+		return cl.NewU512(n)
+	}
+	return nil, fmt.Errorf("can not encode number as %s", clType)
+}
+
+func stringValueOf(s string, clType string) (cl.CLTypedAndToBytes, error) {
+	switch clType {
+	case "", "string":
+		return cl.NewString(s), nil
+	case "key":
+		return cl.ParseKey(s)
+	case "uref":
+		return cl.ParseURef(s)
+	case "public_key":
+		return cl.ParsePublicKey(s)
+	case "unit":
+		return cl.NewUnit(), nil
+	case "i32", "i64", "u8", "u32", "u64", "u128", "u256", "u512":
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid number string: %s", s)
+		}
+		return numberOf(n, clType)
+	}
+	return nil, fmt.Errorf("can not encode string as %s", clType)
+}
+
+func listValueOf(rv reflect.Value, spec argSpec) (cl.CLTypedAndToBytes, error) {
+	elemType, err := clTypeOf(rv.Type().Elem(), spec)
+	if err != nil {
+		return nil, err
+	}
+	list, err := cl.NewList(elemType)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		e, err := clValueOf(rv.Index(i), spec)
+		if err != nil {
+			return nil, err
+		}
+		if err = list.Push(e); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func mapValueOf(rv reflect.Value) (cl.CLTypedAndToBytes, error) {
+	keyType, err := clTypeOf(rv.Type().Key(), argSpec{})
+	if err != nil {
+		return nil, err
+	}
+	valueType, err := clTypeOf(rv.Type().Elem(), argSpec{})
+	if err != nil {
+		return nil, err
+	}
+	type entry struct {
+		key, value cl.CLTypedAndToBytes
+	}
+	entries := make([]entry, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		k, err := clValueOf(iter.Key(), argSpec{})
+		if err != nil {
+			return nil, err
+		}
+		v, err := clValueOf(iter.Value(), argSpec{})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{k, v})
+	}
+	// go maps are unordered, sort by key bytes to keep the serialized bytes stable
+	sort.Slice(entries, func(i, j int) bool {
+		return string(entries[i].key.ToBytes()) < string(entries[j].key.ToBytes())
+	})
+	m := cl.NewMap(keyType, valueType)
+	for _, e := range entries {
+		if err := m.Set(e.key, e.value); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+var (
+	keyType       = reflect.TypeOf(&cl.Key{})
+	urefType      = reflect.TypeOf(&cl.URef{})
+	publicKeyType = reflect.TypeOf(&cl.PublicKey{})
+	boolType      = reflect.TypeOf(&cl.Bool{})
+	stringType    = reflect.TypeOf(&cl.String{})
+)
+
+var specTags = map[string]int{
+	"bool": cl.TagBool, "i32": cl.TagI32, "i64": cl.TagI64, "u8": cl.TagU8, "u32": cl.TagU32,
+	"u64": cl.TagU64, "u128": cl.TagU128, "u256": cl.TagU256, "u512": cl.TagU512, "unit": cl.TagUnit,
+	"string": cl.TagString, "key": cl.TagKey, "uref": cl.TagURef, "public_key": cl.TagPublicKey,
+}
+
+// clTypeOf returns the cl type of a go type without a value, it is needed by None and empty lists
+func clTypeOf(rt reflect.Type, spec argSpec) (*cl.CLType, error) {
+	if spec.option {
+		inner, err := clTypeOf(rt, argSpec{clType: spec.clType})
+		if err != nil {
+			return nil, err
+		}
+		return cl.NewOptionType(inner), nil
+	}
+	if tag, ok := specTags[spec.clType]; ok {
+		return cl.NewCLType(tag), nil
+	}
+	switch rt {
+	case keyType:
+		return cl.NewCLType(cl.TagKey), nil
+	case urefType:
+		return cl.NewCLType(cl.TagURef), nil
+	case publicKeyType:
+		return cl.NewCLType(cl.TagPublicKey), nil
+	case boolType:
+		return cl.NewCLType(cl.TagBool), nil
+	case stringType:
+		return cl.NewCLType(cl.TagString), nil
+	case bigIntType, reflect.PtrTo(bigIntType):
+		return cl.NewCLType(cl.TagU512), nil
+	}
+	switch rt.Kind() {
+	case reflect.Bool:
+		return cl.NewCLType(cl.TagBool), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cl.NewCLType(specTags[defaultSpec(spec.clType, rt.Kind())]), nil
+	case reflect.String:
+		return cl.NewCLType(cl.TagString), nil
+	case reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 && (spec.clType == "" || spec.clType == "byte_array") {
+			return cl.NewByteArrayType(uint32(rt.Len())), nil
+		}
+		fallthrough
+	case reflect.Slice:
+		elem, err := clTypeOf(rt.Elem(), spec)
+		if err != nil {
+			return nil, err
+		}
+		return cl.NewListType(elem), nil
+	case reflect.Map:
+		k, err := clTypeOf(rt.Key(), argSpec{})
+		if err != nil {
+			return nil, err
+		}
+		v, err := clTypeOf(rt.Elem(), argSpec{})
+		if err != nil {
+			return nil, err
+		}
+		return cl.NewMapType(k, v), nil
+	case reflect.Ptr:
+		inner, err := clTypeOf(rt.Elem(), spec)
+		if err != nil {
+			return nil, err
+		}
+		return cl.NewOptionType(inner), nil
+	}
+	return nil, fmt.Errorf("can not infer cl type of go type %s", rt)
+}
+
+func setGoValue(rv reflect.Value, value cl.CLTypedAndToBytes, spec argSpec) error {
+	if option, ok := value.(*cl.Option); ok {
+		if !option.IsSome() {
+			if rv.Kind() != reflect.Ptr && rv.Kind() != reflect.Interface {
+				return errors.New("can not store None in a non pointer field")
+			}
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		value = option.Value()
+		if isOptionPtr(rv.Type()) {
+			elem := reflect.New(rv.Type().Elem())
+			if err := setGoValue(elem.Elem(), value, argSpec{clType: spec.clType}); err != nil {
+				return err
+			}
+			rv.Set(elem)
+			return nil
+		}
+	}
+	if reflect.TypeOf(value).AssignableTo(rv.Type()) {
+		rv.Set(reflect.ValueOf(value))
+		return nil
+	}
+	switch v := value.(type) {
+	case *cl.Bool:
+		if rv.Kind() == reflect.Bool {
+			rv.SetBool(v.Value())
+			return nil
+		}
+	case *cl.String:
+		if rv.Kind() == reflect.String {
+			rv.SetString(v.Value())
+			return nil
+		}
+	case *cl.Key, *cl.URef, *cl.PublicKey:
+		if rv.Kind() == reflect.String {
+			rv.SetString(v.(fmt.Stringer).String())
+			return nil
+		}
+	case *cl.ByteArray:
+		switch {
+		case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+			rv.SetBytes(append([]byte{}, v.Value()...))
+			return nil
+		case rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 && rv.Len() == len(v.Value()):
+			reflect.Copy(rv, reflect.ValueOf(v.Value()))
+			return nil
+		}
+	case *cl.List:
+		if rv.Kind() == reflect.Slice {
+			s := reflect.MakeSlice(rv.Type(), v.Len(), v.Len())
+			for i, e := range v.Value() {
+				if err := setGoValue(s.Index(i), e, argSpec{clType: spec.clType}); err != nil {
+					return err
+				}
+			}
+			rv.Set(s)
+			return nil
+		}
+	case *cl.Map:
+		if rv.Kind() == reflect.Map {
+			m := reflect.MakeMapWithSize(rv.Type(), v.Len())
+			for _, e := range v.Value() {
+				k := reflect.New(rv.Type().Key()).Elem()
+				if err := setGoValue(k, e.Key, argSpec{}); err != nil {
+					return err
+				}
+				val := reflect.New(rv.Type().Elem()).Elem()
+				if err := setGoValue(val, e.Value, argSpec{}); err != nil {
+					return err
+				}
+				m.SetMapIndex(k, val)
+			}
+			rv.Set(m)
+			return nil
+		}
+	case cl.BigNumber:
+		return setGoNumber(rv, v.Value())
+	}
+	return fmt.Errorf("can not store %s in go type %s", cl.TypeOf(value), rv.Type())
+}
+
+func setGoNumber(rv reflect.Value, n *big.Int) error {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !n.IsInt64() || rv.OverflowInt(n.Int64()) {
+			return fmt.Errorf("%s overflows go type %s", n, rv.Type())
+		}
+		rv.SetInt(n.Int64())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !n.IsUint64() || rv.OverflowUint(n.Uint64()) {
+			return fmt.Errorf("%s overflows go type %s", n, rv.Type())
+		}
+		rv.SetUint(n.Uint64())
+		return nil
+	case reflect.String:
+		rv.SetString(n.String())
+		return nil
+	}
+	switch rv.Type() {
+	case bigIntType:
+		rv.Set(reflect.ValueOf(*n))
+		return nil
+	case reflect.PtrTo(bigIntType):
+		rv.Set(reflect.ValueOf(n))
+		return nil
+	}
+	return fmt.Errorf("can not store number in go type %s", rv.Type())
+}
@@ -0,0 +1,182 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type transferArgs struct {
+	Amount *big.Int      `casper:"amount"`
+	Target *cl.PublicKey `casper:"target"`
+	Id     *uint64       `casper:"id"`
+	Memo   string
+}
+
+func TestRuntimeArgsFrom(t *testing.T) {
+	target, err := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := NewTransfer(big.NewInt(1000), target, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uint64(1)
+	ra, err := RuntimeArgsFrom(&transferArgs{Amount: big.NewInt(1000), Target: target, Id: &id, Memo: "ignored"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ra.ToBytes(), session.args.ToBytes()) {
+		t.Fatalf("args bytes error: %s", hex.EncodeToString(ra.ToBytes()))
+	}
+
+	var out transferArgs
+	if err := ra.Unmarshal(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Amount.Cmp(big.NewInt(1000)) != 0 || !out.Target.Equal(target) || out.Id == nil || *out.Id != 1 {
+		t.Fatalf("unmarshal error: %+v", out)
+	}
+}
+
+type tokenArgs struct {
+	Recipient string            `casper:"recipient,key"`
+	Amount    *big.Int          `casper:"amount,u256"`
+	Decimals  uint8             `casper:"decimals"`
+	Holders   []string          `casper:"holders"`
+	Balances  map[string]uint64 `casper:"balances"`
+	Hash      [32]byte          `casper:"hash"`
+	Owner     *cl.Key           `casper:"owner,option"`
+	Memo      *string           `casper:"memo"`
+}
+
+func TestRuntimeArgs_Unmarshal(t *testing.T) {
+	in := tokenArgs{
+		Recipient: "account-hash-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840",
+		Amount:    big.NewInt(5000),
+		Decimals:  9,
+		Holders:   []string{"alice", "bob"},
+		Balances:  map[string]uint64{"bob": 2, "alice": 1},
+		Hash:      [32]byte{1, 2, 3},
+	}
+	ra, err := RuntimeArgsFrom(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := []string{"Key", "U256", "U8", "List(String)", "Map(String, U64)", "ByteArray(32)", "Option(Key)", "Option(String)"}
+	for i, arg := range ra.args {
+		if arg.value.CLType().String() != types[i] {
+			t.Fatalf("arg %s type error: %s", arg.name, arg.value.CLType())
+		}
+	}
+	// the map entries are sorted, the bytes must be stable
+	again, _ := RuntimeArgsFrom(in)
+	if !bytes.Equal(ra.ToBytes(), again.ToBytes()) {
+		t.Fatal("map bytes are not stable")
+	}
+
+	// decode from bytes to check the decoded values are accepted too
+	decoded := RuntimeArgs{}
+	for _, arg := range ra.args {
+		value, err := cl.CLValueFromBytes(arg.value.ToBytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := decoded.InsertCLValue(arg.name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out tokenArgs
+	if err := decoded.Unmarshal(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("unmarshal error: %+v", out)
+	}
+}
+
+func TestRuntimeArgsFrom_Error(t *testing.T) {
+	cases := []interface{}{
+		nil,
+		1,
+		struct {
+			A uint8 `casper:"a"`
+			B uint8 `casper:"a"`
+		}{},
+		struct {
+			A int64 `casper:"a,u8"`
+		}{A: 256},
+		struct {
+			A string `casper:"a,key"`
+		}{A: "bad-key"},
+		struct {
+			A *big.Int `casper:"a"`
+		}{},
+		struct {
+			A float64 `casper:"a"`
+		}{},
+	}
+	for i, c := range cases {
+		if _, err := RuntimeArgsFrom(c); err == nil {
+			t.Fatalf("case %d: expect error", i)
+		}
+	}
+
+	var out struct {
+		A uint8 `casper:"a"`
+	}
+	ra, _ := RuntimeArgsFrom(struct {
+		A uint64 `casper:"a"`
+	}{A: 300})
+	if err := ra.Unmarshal(&out); err == nil {
+		t.Fatal("expect overflow error")
+	}
+	if err := (RuntimeArgs{}).Unmarshal(&out); err == nil {
+		t.Fatal("expect missing arg error")
+	}
+}
+
+func TestRuntimeArgsFrom_JSON(t *testing.T) {
+	in := struct {
+		A int32  `casper:"a"`
+		B int64  `casper:"b"`
+		C uint8  `casper:"c"`
+		D uint32 `casper:"d"`
+		E uint64 `casper:"e"`
+	}{-1, -2, 3, 4, 5}
+	ra, err := RuntimeArgsFrom(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(ra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var args [][2]json.RawMessage
+	if err := json.Unmarshal(data, &args); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"-1", "-2", "3", "4", "5"}
+	for i, arg := range args {
+		var v struct {
+			Parsed json.RawMessage `json:"parsed"`
+		}
+		if err := json.Unmarshal(arg[1], &v); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Trim(string(v.Parsed), `"`) != expect[i] {
+			t.Errorf("arg %s parsed error: %s", arg[0], v.Parsed)
+		}
+	}
+	out := in
+	out.A, out.E = 0, 0
+	if err := ra.Unmarshal(&out); err != nil || out != in {
+		t.Fatalf("unmarshal error: %v %+v", err, out)
+	}
+}
@@ -1,20 +1,279 @@
 package deploy
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"github.com/JFJun/casperlabs-go/keys"
+	"github.com/JFJun/casperlabs-go/keys/blake2b"
 	"math/big"
 )
 
+const (
+	hashLen = 32
+	// untagged signature len of both ed25519 and secp256k1
+	signatureLen = 64
+)
+
+var (
+	ErrInvalidApproval = errors.New("invalid approval")
+	ErrHashMismatch    = errors.New("deploy hash mismatch")
+)
+
+type Deploy struct {
+	Hash      []byte
+	Header    *DeployHeader
+	Payment   ExecutableDeployItem
+	Session   ExecutableDeployItem
+	Approvals []*Approval
+}
+
+type DeployHeader struct {
+	Account *cl.PublicKey
+	// unix timestamp in milliseconds
+	Timestamp uint64
+	// time to live in milliseconds
+	TTL          uint64
+	GasPrice     uint64
+	BodyHash     []byte
+	Dependencies [][]byte
+	ChainName    string
+}
+
+type Approval struct {
+	Signer *cl.PublicKey
+	// signature prefixed with the algorithm tag of the signer
+	Signature []byte
+}
+
 func StandardPayment(paymentAmount *big.Int) (*ModuleBytes, error) {
 	u512, err := cl.NewU512(paymentAmount)
 	if err != nil {
 		return nil, err
 	}
 
-	argMap := map[string]cl.CLTypedAndToBytes{}
-	argMap["amount"] = u512
-	ra := RuntimeArgs{
-		args: argMap,
+	var ra RuntimeArgs
+	if err := ra.Insert("amount", u512); err != nil {
+		return nil, err
 	}
 	return NewModuleBytes([]byte{}, ra), nil
 }
+
+// NewTransfer builds a native transfer session,
+// target is the public key or the account hash(ByteArray 32) of the receiver
+func NewTransfer(amount *big.Int, target cl.CLTypedAndToBytes, id uint64) (*Transfer, error) {
+	if target == nil {
+		return nil, errors.New("transfer target is required")
+	}
+	u512, err := cl.NewU512(amount)
+	if err != nil {
+		return nil, err
+	}
+	idValue, err := cl.NewU64(id)
+	if err != nil {
+		return nil, err
+	}
+	var ra RuntimeArgs
+	if err := ra.Insert("amount", u512); err != nil {
+		return nil, err
+	}
+	if err := ra.Insert("target", target); err != nil {
+		return nil, err
+	}
+	if err := ra.Insert("id", cl.NewSomeOption(idValue)); err != nil {
+		return nil, err
+	}
+	return &Transfer{
+		tag:  tagTransfer,
+		args: ra,
+	}, nil
+}
+
+func MakeDeploy(p *DeployParam, session, payment ExecutableDeployItem) (*Deploy, error) {
+	if p.accountPublicKey == nil {
+		return nil, errors.New("deploy account is required")
+	}
+	if session == nil || payment == nil {
+		return nil, errors.New("deploy session and payment are required")
+	}
+	dependencies := make([][]byte, 0, len(p.dependencies))
+	for _, dep := range p.dependencies {
+		if len(dep) != hashLen {
+			return nil, fmt.Errorf("invalid dependency len: %d", len(dep))
+		}
+		dependencies = append(dependencies, dep)
+	}
+	d := &Deploy{
+		Header: &DeployHeader{
+			Account:      p.accountPublicKey,
+			Timestamp:    p.timestamp,
+			TTL:          p.ttl,
+			GasPrice:     p.gasPrice,
+			Dependencies: dependencies,
+			ChainName:    p.chainName,
+		},
+		Payment:   payment,
+		Session:   session,
+		Approvals: []*Approval{},
+	}
+	d.Header.BodyHash = d.ComputeBodyHash()
+	d.Hash = d.Header.ComputeHash()
+	return d, nil
+}
+
+// ComputeBodyHash returns blake2b256(payment bytes | session bytes)
+func (d *Deploy) ComputeBodyHash() []byte {
+	return blake2b.Hash(bytes.Join([][]byte{
+		d.Payment.ToBytes(),
+		d.Session.ToBytes(),
+	}, []byte{}))
+}
+
+// ToBytes serializes the whole deploy: header | hash | payment | session | approvals
+func (d *Deploy) ToBytes() []byte {
+	approvals := cl.ToBytesU32(uint32(len(d.Approvals)))
+	for _, a := range d.Approvals {
+		approvals = append(approvals, a.ToBytes()...)
+	}
+	return bytes.Join([][]byte{
+		d.Header.ToBytes(),
+		d.Hash,
+		d.Payment.ToBytes(),
+		d.Session.ToBytes(),
+		approvals,
+	}, []byte{})
+}
+
+// ComputeHash returns the deploy hash, blake2b256(header bytes)
+func (h *DeployHeader) ComputeHash() []byte {
+	return blake2b.Hash(h.ToBytes())
+}
+
+func (h *DeployHeader) ToBytes() []byte {
+	deps := cl.ToBytesU32(uint32(len(h.Dependencies)))
+	for _, dep := range h.Dependencies {
+		deps = append(deps, dep...)
+	}
+	return bytes.Join([][]byte{
+		h.Account.ToBytes(),
+		cl.ToBytesU64(h.Timestamp),
+		cl.ToBytesU64(h.TTL),
+		cl.ToBytesU64(h.GasPrice),
+		h.BodyHash,
+		deps,
+		cl.ToBytesString(h.ChainName),
+	}, []byte{})
+}
+
+func (a *Approval) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		a.Signer.ToBytes(),
+		a.Signature,
+	}, []byte{})
+}
+
+// Sign signs the deploy hash and appends the approval,
+// the holder must contain both the private key and the public key
+func (d *Deploy) Sign(holder keys.KeyHolder) error {
+	accountHex, err := holder.AccountHex()
+	if err != nil {
+		return err
+	}
+	signer, err := cl.ParsePublicKey(accountHex)
+	if err != nil {
+		return err
+	}
+	sig, err := holder.Sign(d.Hash)
+	if err != nil {
+		return fmt.Errorf("sign deploy error: %v", err)
+	}
+	return d.addApproval(&Approval{
+		Signer:    signer,
+		Signature: append([]byte{signer.Tag()}, sig...),
+	})
+}
+
+// AddApproval appends a signature collected elsewhere, e.g. from the other signers of a multi-sig account.
+// The signature may be prefixed with the algorithm tag or not, it must be valid for the deploy hash.
+func (d *Deploy) AddApproval(signer *cl.PublicKey, signature []byte) error {
+	if signer == nil {
+		return fmt.Errorf("%w: signer is required", ErrInvalidApproval)
+	}
+	sig := signature
+	if len(sig) != signatureLen+1 || sig[0] != signer.Tag() {
+		sig = append([]byte{signer.Tag()}, signature...)
+	}
+	return d.addApproval(&Approval{
+		Signer:    signer,
+		Signature: sig,
+	})
+}
+
+func (d *Deploy) addApproval(a *Approval) error {
+	for _, approval := range d.Approvals {
+		if approval.Signer.Equal(a.Signer) {
+			return fmt.Errorf("%w: %s already approved", ErrInvalidApproval, a.Signer)
+		}
+	}
+	if err := a.Verify(d.Hash); err != nil {
+		return err
+	}
+	d.Approvals = append(d.Approvals, a)
+	return nil
+}
+
+// VerifyApprovals checks the signature of every approval against the deploy hash
+func (d *Deploy) VerifyApprovals() error {
+	for i, a := range d.Approvals {
+		if err := a.Verify(d.Hash); err != nil {
+			return fmt.Errorf("approval %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Verify checks the tagged signature with the public key of the signer
+func (a *Approval) Verify(deployHash []byte) error {
+	if a.Signer == nil {
+		return fmt.Errorf("%w: signer is required", ErrInvalidApproval)
+	}
+	if len(a.Signature) < 2 || a.Signature[0] != a.Signer.Tag() {
+		return fmt.Errorf("%w: signature of %s is not tagged with %s", ErrInvalidApproval, a.Signer, a.Signer.Algorithm())
+	}
+	holder := keys.NewKeyHolder(nil, a.Signer.Raw(), keys.SignatureAlgorithm(a.Signer.Algorithm()))
+	ok, err := holder.Verify(deployHash, a.Signature[1:])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidApproval, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: bad signature of %s", ErrInvalidApproval, a.Signer)
+	}
+	return nil
+}
+
+// Verify checks a deploy received from others: the hashes are recomputed from the decoded contents
+// and the deploy must be approved with valid signatures
+func (d *Deploy) Verify() error {
+	if err := d.CheckHash(); err != nil {
+		return err
+	}
+	if len(d.Approvals) == 0 {
+		return fmt.Errorf("%w: deploy is not approved", ErrInvalidApproval)
+	}
+	return d.VerifyApprovals()
+}
+
+// CheckHash recomputes the body hash and the deploy hash and compares them with the stored ones
+func (d *Deploy) CheckHash() error {
+	if d.Header == nil || d.Payment == nil || d.Session == nil {
+		return errors.New("deploy header, payment and session are required")
+	}
+	if !bytes.Equal(d.ComputeBodyHash(), d.Header.BodyHash) {
+		return fmt.Errorf("%w: body hash mismatch", ErrHashMismatch)
+	}
+	if !bytes.Equal(d.Header.ComputeHash(), d.Hash) {
+		return fmt.Errorf("%w: deploy hash mismatch", ErrHashMismatch)
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+package deploy
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const timestampLayout = "2006-01-02T15:04:05.000Z"
+
+type deployJson struct {
+	Hash      string               `json:"hash"`
+	Header    *deployHeaderJson    `json:"header"`
+	Payment   ExecutableDeployItem `json:"payment"`
+	Session   ExecutableDeployItem `json:"session"`
+	Approvals []*approvalJson      `json:"approvals"`
+}
+
+type deployHeaderJson struct {
+	Account      string   `json:"account"`
+	Timestamp    string   `json:"timestamp"`
+	TTL          string   `json:"ttl"`
+	GasPrice     uint64   `json:"gas_price"`
+	BodyHash     string   `json:"body_hash"`
+	Dependencies []string `json:"dependencies"`
+	ChainName    string   `json:"chain_name"`
+}
+
+type approvalJson struct {
+	Signer    string `json:"signer"`
+	Signature string `json:"signature"`
+}
+
+// MarshalJSON encodes the deploy in the format accepted by account_put_deploy
+func (d *Deploy) MarshalJSON() ([]byte, error) {
+	approvals := make([]*approvalJson, 0, len(d.Approvals))
+	for _, a := range d.Approvals {
+		approvals = append(approvals, &approvalJson{
+			Signer:    a.Signer.String(),
+			Signature: hex.EncodeToString(a.Signature),
+		})
+	}
+	return json.Marshal(deployJson{
+		Hash:      hex.EncodeToString(d.Hash),
+		Header:    d.Header.toJson(),
+		Payment:   d.Payment,
+		Session:   d.Session,
+		Approvals: approvals,
+	})
+}
+
+// UnmarshalJSON decodes the deploy json of the node, the hashes and approvals are not checked,
+// use CheckHash and VerifyApprovals
+func (d *Deploy) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Hash      string            `json:"hash"`
+		Header    *deployHeaderJson `json:"header"`
+		Payment   json.RawMessage   `json:"payment"`
+		Session   json.RawMessage   `json:"session"`
+		Approvals []*approvalJson   `json:"approvals"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Header == nil {
+		return errors.New("deploy header is required")
+	}
+	hash, err := decodeHash(v.Hash)
+	if err != nil {
+		return fmt.Errorf("invalid deploy hash: %v", err)
+	}
+	header, err := v.Header.toHeader()
+	if err != nil {
+		return err
+	}
+	payment, err := unmarshalDeployItem(v.Payment)
+	if err != nil {
+		return fmt.Errorf("invalid payment: %v", err)
+	}
+	session, err := unmarshalDeployItem(v.Session)
+	if err != nil {
+		return fmt.Errorf("invalid session: %v", err)
+	}
+	approvals := make([]*Approval, 0, len(v.Approvals))
+	for _, a := range v.Approvals {
+		if a == nil {
+			return errors.New("approval is null")
+		}
+		approval, err := a.toApproval()
+		if err != nil {
+			return err
+		}
+		approvals = append(approvals, approval)
+	}
+	*d = Deploy{
+		Hash:      hash,
+		Header:    header,
+		Payment:   payment,
+		Session:   session,
+		Approvals: approvals,
+	}
+	return nil
+}
+
+// ParseJSON decodes a deploy json received from other systems, it accepts the bare deploy
+// or the envelope used by account_put_deploy and info_get_deploy: {"deploy": {...}}.
+// The CLValues are rebuilt from their bytes, use Verify to check the hashes and approvals
+func ParseJSON(data []byte) (*Deploy, error) {
+	var envelope struct {
+		Deploy json.RawMessage `json:"deploy"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if len(envelope.Deploy) != 0 {
+		data = envelope.Deploy
+	}
+	d := &Deploy{}
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (h *deployHeaderJson) toHeader() (*DeployHeader, error) {
+	account, err := cl.ParsePublicKey(h.Account)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deploy account: %v", err)
+	}
+	timestamp, err := parseTimestamp(h.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := parseTTL(h.TTL)
+	if err != nil {
+		return nil, err
+	}
+	bodyHash, err := decodeHash(h.BodyHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body hash: %v", err)
+	}
+	deps := make([][]byte, 0, len(h.Dependencies))
+	for _, dep := range h.Dependencies {
+		b, err := decodeHash(dep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dependency: %v", err)
+		}
+		deps = append(deps, b)
+	}
+	return &DeployHeader{
+		Account:      account,
+		Timestamp:    timestamp,
+		TTL:          ttl,
+		GasPrice:     h.GasPrice,
+		BodyHash:     bodyHash,
+		Dependencies: deps,
+		ChainName:    h.ChainName,
+	}, nil
+}
+
+func (a *approvalJson) toApproval() (*Approval, error) {
+	signer, err := cl.ParsePublicKey(a.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval signer: %v", err)
+	}
+	sig, err := hex.DecodeString(a.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval signature: %v", err)
+	}
+	return &Approval{
+		Signer:    signer,
+		Signature: sig,
+	}, nil
+}
+
+func decodeHash(s string) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != hashLen {
+		return nil, fmt.Errorf("hash len must be %d, got %d", hashLen, len(b))
+	}
+	return b, nil
+}
+
+func (h *DeployHeader) toJson() *deployHeaderJson {
+	deps := make([]string, 0, len(h.Dependencies))
+	for _, dep := range h.Dependencies {
+		deps = append(deps, hex.EncodeToString(dep))
+	}
+	return &deployHeaderJson{
+		Account:      h.Account.String(),
+		Timestamp:    formatTimestamp(h.Timestamp),
+		TTL:          formatTTL(h.TTL),
+		GasPrice:     h.GasPrice,
+		BodyHash:     hex.EncodeToString(h.BodyHash),
+		Dependencies: deps,
+		ChainName:    h.ChainName,
+	}
+}
+
+func formatTimestamp(ms uint64) string {
+	return time.Unix(0, int64(ms)*int64(time.Millisecond)).UTC().Format(timestampLayout)
+}
+
+func parseTimestamp(s string) (uint64, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %v", err)
+	}
+	if t.Before(time.Unix(0, 0)) {
+		return 0, fmt.Errorf("invalid timestamp: %s is before 1970", s)
+	}
+	return uint64(t.UnixNano() / int64(time.Millisecond)), nil
+}
+
+// the year and the month of humantime are 365.25 days and 30.44 days
+const (
+	ttlYear  = 31557600 * 1000
+	ttlMonth = 2630016 * 1000
+	ttlDay   = 24 * 60 * 60 * 1000
+)
+
+var ttlUnits = []struct {
+	ms       uint64
+	singular string
+	plural   string
+}{
+	{ttlYear, "year", "years"},
+	{ttlMonth, "month", "months"},
+	{ttlDay, "day", "days"},
+	{60 * 60 * 1000, "h", "h"},
+	{60 * 1000, "m", "m"},
+	{1000, "s", "s"},
+	{1, "ms", "ms"},
+}
+
+// formatTTL formats the ttl the same way as the node does, e.g. 30m, 1h 30m, 1day, 1month 2days
+func formatTTL(ms uint64) string {
+	if ms == 0 {
+		return "0s"
+	}
+	var parts []string
+	for _, u := range ttlUnits {
+		n := ms / u.ms
+		if n == 0 {
+			continue
+		}
+		ms -= n * u.ms
+		unit := u.singular
+		if n > 1 {
+			unit = u.plural
+		}
+		parts = append(parts, fmt.Sprintf("%d%s", n, unit))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ttlUnitAliases are the humantime units accepted by the node, in nanoseconds
+var ttlUnitAliases = map[string]uint64{
+	"ns": 1, "nsec": 1, "nanos": 1,
+	"us": 1000, "usec": 1000,
+	"ms": 1e6, "msec": 1e6, "millis": 1e6,
+	"s": 1000 * 1e6, "sec": 1000 * 1e6, "secs": 1000 * 1e6, "second": 1000 * 1e6, "seconds": 1000 * 1e6,
+	"m": 60 * 1000 * 1e6, "min": 60 * 1000 * 1e6, "mins": 60 * 1000 * 1e6, "minute": 60 * 1000 * 1e6, "minutes": 60 * 1000 * 1e6,
+	"h": 60 * 60 * 1000 * 1e6, "hr": 60 * 60 * 1000 * 1e6, "hrs": 60 * 60 * 1000 * 1e6, "hour": 60 * 60 * 1000 * 1e6, "hours": 60 * 60 * 1000 * 1e6,
+	"d": ttlDay * 1e6, "day": ttlDay * 1e6, "days": ttlDay * 1e6,
+	"w": 7 * ttlDay * 1e6, "week": 7 * ttlDay * 1e6, "weeks": 7 * ttlDay * 1e6,
+	"M": ttlMonth * 1e6, "month": ttlMonth * 1e6, "months": ttlMonth * 1e6,
+	"y": ttlYear * 1e6, "year": ttlYear * 1e6, "years": ttlYear * 1e6,
+}
+
+// parseTTL parses the humantime format, e.g. 30m, 1h 30m, 1day, 2h30m, 1month 2weeks.
+// The ttl is in milliseconds, the fraction of us and ns is truncated like the node does
+func parseTTL(s string) (uint64, error) {
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return 0, errors.New("invalid ttl: empty")
+	}
+	// ms holds the units of at least one millisecond, ns holds us and ns
+	var ms, ns uint64
+	add := func(sum *uint64, n, unit uint64) error {
+		if n > (math.MaxUint64-*sum)/unit {
+			return fmt.Errorf("invalid ttl: %s overflows", s)
+		}
+		*sum += n * unit
+		return nil
+	}
+	for rest != "" {
+		i := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsDigit(r) })
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid ttl: %s", s)
+		}
+		n, err := strconv.ParseUint(rest[:i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ttl: %s", s)
+		}
+		rest = rest[i:]
+		j := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsLetter(r) })
+		if j < 0 {
+			j = len(rest)
+		}
+		unit, ok := ttlUnitAliases[rest[:j]]
+		if !ok {
+			return 0, fmt.Errorf("invalid ttl unit %q: %s", rest[:j], s)
+		}
+		if unit%1e6 == 0 {
+			err = add(&ms, n, unit/1e6)
+		} else {
+			err = add(&ns, n, unit)
+		}
+		if err != nil {
+			return 0, err
+		}
+		rest = strings.TrimSpace(rest[j:])
+	}
+	if err := add(&ms, ns/1e6, 1); err != nil {
+		return 0, err
+	}
+	return ms, nil
+}
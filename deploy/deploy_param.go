@@ -1,7 +1,18 @@
 package deploy
 
-type param struct {
-	accountPublicKey []byte
+import (
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"time"
+)
+
+const (
+	DefaultGasPrice = 1
+	// 30 minutes, in milliseconds
+	DefaultTTL = 30 * 60 * 1000
+)
+
+type DeployParam struct {
+	accountPublicKey *cl.PublicKey
 	chainName        string
 	gasPrice         uint64
 	ttl              uint64
@@ -9,6 +20,39 @@ type param struct {
 	timestamp        uint64
 }
 
+func NewDeployParam(accountPublicKey *cl.PublicKey, chainName string) *DeployParam {
+	return &DeployParam{
+		accountPublicKey: accountPublicKey,
+		chainName:        chainName,
+		gasPrice:         DefaultGasPrice,
+		ttl:              DefaultTTL,
+		timestamp:        uint64(time.Now().UnixNano() / int64(time.Millisecond)),
+	}
+}
+
+func (p *DeployParam) SetGasPrice(gasPrice uint64) *DeployParam {
+	p.gasPrice = gasPrice
+	return p
+}
+
+// SetTTL sets the time to live in milliseconds
+func (p *DeployParam) SetTTL(ttl uint64) *DeployParam {
+	p.ttl = ttl
+	return p
+}
+
+// SetTimestamp sets the unix timestamp in milliseconds
+func (p *DeployParam) SetTimestamp(timestamp uint64) *DeployParam {
+	p.timestamp = timestamp
+	return p
+}
+
+// SetDependencies sets the deploy hashes that must be executed before this deploy
+func (p *DeployParam) SetDependencies(dependencies [][]byte) *DeployParam {
+	p.dependencies = dependencies
+	return p
+}
+
 type transaction struct {
 	amount      string
 	target      []byte
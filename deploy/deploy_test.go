@@ -1,9 +1,15 @@
 package deploy
 
 import (
+	"bytes"
 	"encoding/hex"
-	"fmt"
+	"encoding/json"
+	"errors"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"github.com/JFJun/casperlabs-go/keys"
+	"github.com/JFJun/casperlabs-go/keys/blake2b"
 	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -12,5 +18,391 @@ func TestStandardPayment(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	fmt.Printf("%v\n", hex.EncodeToString(payment.args.args["amount"].ToBytes()	))
+	if payment.Args().Get("amount") == nil {
+		t.Fatal("standard payment without amount")
+	}
+}
+
+func TestNewTransfer(t *testing.T) {
+	target, err := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := NewTransfer(big.NewInt(1000), target, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "05" + "03000000" +
+		"06000000616d6f756e74" + "0300000002e80308" +
+		"06000000746172676574" + "21000000" + "0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c" + "16" +
+		"020000006964" + "09000000010100000000000000" + "0d05"
+	if hex.EncodeToString(session.ToBytes()) != expect {
+		t.Fatalf("transfer bytes error: %s", hex.EncodeToString(session.ToBytes()))
+	}
+}
+
+func TestMakeDeploy(t *testing.T) {
+	priv, _ := hex.DecodeString("b98e274c47887ff4a72a8921bbaa045ea12894cebb7ed6d99e76dbdfc784df5b66065ad33dc8adaeb8677690696918aed102be664718434316aca52d51ae3922")
+	pub, _ := hex.DecodeString("66065ad33dc8adaeb8677690696918aed102be664718434316aca52d51ae3922")
+	holder := keys.NewKeyHolder(priv, pub, keys.Ed25519)
+	account, err := cl.NewPublicKey(cl.Ed25519Tag, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := NewTransfer(big.NewInt(2500000000), account, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, err := StandardPayment(big.NewInt(10000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := MakeDeploy(NewDeployParam(account, "casper-test"), session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Sign(holder); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Approvals) != 1 || d.Approvals[0].Signature[0] != cl.Ed25519Tag {
+		t.Fatal("approval error")
+	}
+	ok, err := holder.Verify(d.Hash, d.Approvals[0].Signature[1:])
+	if err != nil || !ok {
+		t.Fatal("approval signature error")
+	}
+	if _, err = json.Marshal(d); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeployHeader_ToBytes(t *testing.T) {
+	account, _ := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	session, _ := NewTransfer(big.NewInt(1000), account, 1)
+	payment, _ := StandardPayment(big.NewInt(10000))
+	dep, _ := hex.DecodeString("20f1190d4ddc06246e07d5fd0454d90f3b509936e3d2584350239104e183a000")
+	p := NewDeployParam(account, "casper-test").
+		SetTimestamp(1615364499062).
+		SetTTL(60 * 60 * 1000).
+		SetGasPrice(2).
+		SetDependencies([][]byte{dep})
+	d, err := MakeDeploy(p, session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodyHash := blake2b.Hash(append(payment.ToBytes(), session.ToBytes()...))
+	if !bytes.Equal(d.Header.BodyHash, bodyHash) {
+		t.Fatal("body hash error")
+	}
+	expect := "0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c" +
+		"76263a1b78010000" + "80ee360000000000" + "0200000000000000" +
+		hex.EncodeToString(bodyHash) +
+		"01000000" + "20f1190d4ddc06246e07d5fd0454d90f3b509936e3d2584350239104e183a000" +
+		"0b0000006361737065722d74657374"
+	if hex.EncodeToString(d.Header.ToBytes()) != expect {
+		t.Fatalf("header bytes error: %s", hex.EncodeToString(d.Header.ToBytes()))
+	}
+	if !bytes.Equal(d.Hash, blake2b.Hash(d.Header.ToBytes())) {
+		t.Fatal("deploy hash error")
+	}
+
+	_, err = MakeDeploy(p.SetDependencies([][]byte{dep[:31]}), session, payment)
+	if err == nil {
+		t.Fatal("invalid dependency should be rejected")
+	}
+}
+
+func TestDeploy_MarshalJSON(t *testing.T) {
+	account, _ := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	session, _ := NewTransfer(big.NewInt(1000), account, 1)
+	payment, _ := StandardPayment(big.NewInt(10000))
+	p := NewDeployParam(account, "casper-test").SetTimestamp(1615364499062).SetTTL(90 * 60 * 1000)
+	d, err := MakeDeploy(p, session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	header := v["header"].(map[string]interface{})
+	if header["timestamp"] != "2021-03-10T08:21:39.062Z" {
+		t.Fatalf("timestamp error: %v", header["timestamp"])
+	}
+	if header["ttl"] != "1h 30m" {
+		t.Fatalf("ttl error: %v", header["ttl"])
+	}
+	if v["hash"] != hex.EncodeToString(d.Hash) {
+		t.Fatal("hash error")
+	}
+}
+
+func TestDeploy_Approvals(t *testing.T) {
+	priv1, pub1, _ := keys.GenerateKeys(keys.Ed25519)
+	priv2, pub2, _ := keys.GenerateKeys(keys.Ed25519)
+	account, _ := cl.NewPublicKey(cl.Ed25519Tag, pub1)
+	cosigner, _ := cl.NewPublicKey(cl.Ed25519Tag, pub2)
+	session, _ := NewTransfer(big.NewInt(2500000000), cosigner, 0)
+	payment, _ := StandardPayment(big.NewInt(10000))
+	d, err := MakeDeploy(NewDeployParam(account, "casper-test"), session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Sign(keys.NewKeyHolder(priv1, pub1, keys.Ed25519)); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Sign(keys.NewKeyHolder(priv1, pub1, keys.Ed25519)); err == nil {
+		t.Fatal("duplicate signer should be rejected")
+	}
+	// the cosigner signs the hash offline, the signature is untagged
+	sig, err := keys.Sign(priv2, d.Hash, keys.Ed25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.AddApproval(account, sig); err == nil {
+		t.Fatal("signature of another signer should be rejected")
+	}
+	if err = d.AddApproval(cosigner, sig); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Approvals) != 2 || d.Approvals[1].Signature[0] != cl.Ed25519Tag || len(d.Approvals[1].Signature) != 65 {
+		t.Fatal("approvals error")
+	}
+	if err = d.VerifyApprovals(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.Approvals[1].Signature[10] ^= 0xff
+	if err = d.VerifyApprovals(); !errors.Is(err, ErrInvalidApproval) {
+		t.Fatalf("tampered signature should be rejected: %v", err)
+	}
+}
+
+func TestDeploy_SignSecp256k1(t *testing.T) {
+	priv, _ := hex.DecodeString("be798eee9bb3fa267e0525a7633260c5d2a9512dd2f96b8d621f560dd233d99a")
+	pub, _ := hex.DecodeString("03447239548b66bdfe334131392dd9db386c054989e2b815fe68fd634c9e4703a1")
+	account, _ := cl.NewPublicKey(cl.Secp256K1Tag, pub)
+	session, _ := NewTransfer(big.NewInt(2500000000), account, 0)
+	payment, _ := StandardPayment(big.NewInt(10000))
+	d, err := MakeDeploy(NewDeployParam(account, "casper-test"), session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Sign(keys.NewKeyHolder(priv, pub, keys.Secp256K1)); err != nil {
+		t.Fatal(err)
+	}
+	sig := d.Approvals[0].Signature
+	if len(sig) != 65 || sig[0] != cl.Secp256K1Tag {
+		t.Fatalf("secp256k1 approval error: %x", sig)
+	}
+	if err = d.VerifyApprovals(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	cases := map[string]uint64{
+		"30m":       30 * 60 * 1000,
+		"1h 30m":    90 * 60 * 1000,
+		"1day":      24 * 60 * 60 * 1000,
+		"2days 1h":  49 * 60 * 60 * 1000,
+		"1h30m":     90 * 60 * 1000,
+		"500ms":     500,
+		"1m 2s 3ms": 62003,
+	}
+	for s, expect := range cases {
+		ms, err := parseTTL(s)
+		if err != nil || ms != expect {
+			t.Fatalf("parse %s error: %d %v", s, ms, err)
+		}
+		if s != "2days 1h" && s != "1h30m" && s != "1m 2s 3ms" {
+			if formatTTL(ms) != s {
+				t.Fatalf("format %d error: %s", ms, formatTTL(ms))
+			}
+		}
+	}
+	for _, s := range []string{"", "30", "m", "1 week", "-1h", "213503982334602days", "18446744073709551615ms 1ms"} {
+		if _, err := parseTTL(s); err == nil {
+			t.Fatalf("%q should be rejected", s)
+		}
+	}
+}
+
+// every humantime unit, format is the form emitted by the node
+func TestParseTTL_Units(t *testing.T) {
+	cases := []struct {
+		ttl    string
+		ms     uint64
+		format string
+	}{
+		{"2000000ns", 2, "2ms"},
+		{"3nsec 4nanos", 0, "0s"},
+		{"1500us", 1, "1ms"},
+		{"2500usec", 2, "2ms"},
+		{"7ms", 7, "7ms"},
+		{"8msec 9millis", 17, "17ms"},
+		{"2s", 2000, "2s"},
+		{"1sec 1secs 1second 1seconds", 4000, "4s"},
+		{"3m", 3 * 60 * 1000, "3m"},
+		{"1min 1mins 1minute 1minutes", 4 * 60 * 1000, "4m"},
+		{"5h", 5 * 60 * 60 * 1000, "5h"},
+		{"1hr 1hrs 1hour 1hours", 4 * 60 * 60 * 1000, "4h"},
+		{"1d", ttlDay, "1day"},
+		{"1day 1days", 2 * ttlDay, "2days"},
+		{"1w", 7 * ttlDay, "7days"},
+		{"1week 1weeks", 14 * ttlDay, "14days"},
+		{"1M", ttlMonth, "1month"},
+		{"1month 1months", 2 * ttlMonth, "2months"},
+		{"1y", ttlYear, "1year"},
+		{"1year 1years", 2 * ttlYear, "2years"},
+		{"1year 2months 3days 4h 5m 6s 7ms", ttlYear + 2*ttlMonth + 3*ttlDay + 4*60*60*1000 + 5*60*1000 + 6*1000 + 7, "1year 2months 3days 4h 5m 6s 7ms"},
+	}
+	for _, c := range cases {
+		ms, err := parseTTL(c.ttl)
+		if err != nil || ms != c.ms {
+			t.Fatalf("parse %s error: %d %v", c.ttl, ms, err)
+		}
+		if formatTTL(ms) != c.format {
+			t.Fatalf("format %s error: %s", c.ttl, formatTTL(ms))
+		}
+		if again, err := parseTTL(c.format); err != nil || again != ms {
+			t.Fatalf("round trip %s error: %d %v", c.format, again, err)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	priv, pub, _ := keys.GenerateKeys(keys.Ed25519)
+	account, _ := cl.NewPublicKey(cl.Ed25519Tag, pub)
+	recipient, _ := cl.ParseKey("account-hash-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840")
+	amount, _ := cl.NewU256(big.NewInt(5000))
+	holders, _ := cl.NewList(cl.NewCLType(cl.TagString), cl.NewString("alice"), cl.NewString("bob"))
+	var args RuntimeArgs
+	_ = args.Insert("recipient", recipient)
+	_ = args.Insert("amount", amount)
+	_ = args.Insert("holders", holders)
+	_ = args.Insert("memo", cl.NewNoneOption(cl.NewCLType(cl.TagString)))
+	session := &StoredContractByName{tag: tagStoredContractByName, name: "erc20", entryPoint: "transfer", args: args}
+	payment, _ := StandardPayment(big.NewInt(1000000000))
+	d, err := MakeDeploy(NewDeployParam(account, "casper-test"), session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Sign(keys.NewKeyHolder(priv, pub, keys.Ed25519)); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(d)
+	envelope := `{"api_version":"1.0.0","deploy":` + string(data) + `,"execution_results":[]}`
+	for _, s := range []string{string(data), envelope} {
+		parsed, err := ParseJSON([]byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(parsed.ToBytes(), d.ToBytes()) {
+			t.Fatal("parsed deploy bytes error")
+		}
+		if err = parsed.Verify(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 5000 -> 6000 in the bytes of the amount arg
+	tampered := strings.Replace(string(data), `"bytes":"028813"`, `"bytes":"027017"`, 1)
+	parsed, err := ParseJSON([]byte(tampered))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = parsed.Verify(); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("tampered args should be rejected: %v", err)
+	}
+	d.Approvals = nil
+	if err = d.Verify(); !errors.Is(err, ErrInvalidApproval) {
+		t.Fatalf("unsigned deploy should be rejected: %v", err)
+	}
+	if _, err = ParseJSON([]byte(`{"deploy":{"hash":"00"}}`)); err == nil {
+		t.Fatal("invalid deploy should be rejected")
+	}
+}
+
+// the example deploy of the account_put_deploy params in the casper-node json-rpc schema
+const nodeDeployJson = `{
+  "deploy": {
+    "hash": "5c9b3b099c1378aa8e4a5f07f59ff1fcdc69a83179427c7e67ae0377d94d93fa",
+    "header": {
+      "account": "01d9bf2148748a85c89da5aad8ee0b0fc2d105fd39d41a4c796536354f0ae2900c",
+      "timestamp": "2020-11-17T00:39:24.072Z",
+      "ttl": "1h",
+      "gas_price": 1,
+      "body_hash": "d53cf72d17278fd47d399013ca389c50d589352f1a12593c0b8e01872a641b50",
+      "dependencies": [
+        "0101010101010101010101010101010101010101010101010101010101010101"
+      ],
+      "chain_name": "casper-example"
+    },
+    "payment": {
+      "StoredContractByName": {
+        "name": "casper-example",
+        "entry_point": "example-entry-point",
+        "args": [
+          [
+            "amount",
+            {
+              "cl_type": "I32",
+              "bytes": "e8030000",
+              "parsed": 1000
+            }
+          ]
+        ]
+      }
+    },
+    "session": {
+      "Transfer": {
+        "args": [
+          [
+            "amount",
+            {
+              "cl_type": "I32",
+              "bytes": "e8030000",
+              "parsed": 1000
+            }
+          ]
+        ]
+      }
+    },
+    "approvals": [
+      {
+        "signer": "01d9bf2148748a85c89da5aad8ee0b0fc2d105fd39d41a4c796536354f0ae2900c",
+        "signature": "014c1a89f92e29dd74fc648f741137d9caf4edba97c5f9799ce0c9aa6b0c9b58db368c64098603dbecef645774c05dff057cb1f91f2cf390bbacce78aa6f084007"
+      }
+    ]
+  }
+}`
+
+func TestParseJSON_Node(t *testing.T) {
+	var envelope struct {
+		Deploy json.RawMessage `json:"deploy"`
+	}
+	if err := json.Unmarshal([]byte(nodeDeployJson), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{nodeDeployJson, string(envelope.Deploy)} {
+		d, err := ParseJSON([]byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = d.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(d.Hash) != "5c9b3b099c1378aa8e4a5f07f59ff1fcdc69a83179427c7e67ae0377d94d93fa" ||
+			d.Header.TTL != 60*60*1000 || d.Header.Timestamp != 1605573564072 || len(d.Approvals) != 1 {
+			t.Fatalf("parsed deploy error: %+v", d.Header)
+		}
+		if _, ok := d.Session.(*Transfer); !ok {
+			t.Fatalf("session type error: %T", d.Session)
+		}
+	}
 }
@@ -1,14 +1,28 @@
 package deploy
 
-import cl "github.com/JFJun/casperlabs-go/clvalue"
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+)
 
-type ExecutableDeployItem struct {
-	moduleBytes                   ModuleBytes
-	storedContractByHash          StoredContractByHash
-	storedContractByName          StoredContractByName
-	storedVersionedContractByHash StoredVersionedContractByHash
-	storedVersionedContractByName StoredVersionedContractByName
-	transfer                      Transfer
+const (
+	tagModuleBytes = iota
+	tagStoredContractByHash
+	tagStoredContractByName
+	tagStoredVersionedContractByHash
+	tagStoredVersionedContractByName
+	tagTransfer
+)
+
+// ExecutableDeployItem is the payment or session part of a deploy
+type ExecutableDeployItem interface {
+	Args() RuntimeArgs
+	ToBytes() []byte
+	json.Marshaler
 }
 
 type ModuleBytes struct {
@@ -17,10 +31,6 @@ type ModuleBytes struct {
 	args        RuntimeArgs
 }
 
-type RuntimeArgs struct {
-	args map[string]cl.CLTypedAndToBytes
-}
-
 type StoredContractByHash struct {
 	tag        int
 	hash       []byte
@@ -30,7 +40,7 @@ type StoredContractByHash struct {
 
 type StoredContractByName struct {
 	tag        int
-	hash       []byte
+	name       string
 	entryPoint string
 	args       RuntimeArgs
 }
@@ -38,15 +48,15 @@ type StoredContractByName struct {
 type StoredVersionedContractByHash struct {
 	tag        int
 	hash       []byte
-	version    int
+	version    *uint32
 	entryPoint string
 	args       RuntimeArgs
 }
 
 type StoredVersionedContractByName struct {
 	tag        int
-	hash       []byte
-	version    int
+	name       string
+	version    *uint32
 	entryPoint string
 	args       RuntimeArgs
 }
@@ -58,7 +68,289 @@ type Transfer struct {
 
 func NewModuleBytes(moduleBytes []byte, args RuntimeArgs) *ModuleBytes {
 	return &ModuleBytes{
+		tag:         tagModuleBytes,
 		moduleBytes: moduleBytes,
 		args:        args,
 	}
 }
+
+// NewStoredContractByHash calls the entry point of the contract stored under the 32 bytes contract hash
+func NewStoredContractByHash(hash []byte, entryPoint string, args RuntimeArgs) (*StoredContractByHash, error) {
+	if len(hash) != hashLen {
+		return nil, fmt.Errorf("invalid contract hash len: %d", len(hash))
+	}
+	if entryPoint == "" {
+		return nil, errors.New("entry point is required")
+	}
+	return &StoredContractByHash{
+		tag:        tagStoredContractByHash,
+		hash:       copyBytes(hash),
+		entryPoint: entryPoint,
+		args:       args,
+	}, nil
+}
+
+// NewStoredContractByName calls the entry point of the contract stored under the named key of the caller
+func NewStoredContractByName(name, entryPoint string, args RuntimeArgs) (*StoredContractByName, error) {
+	if name == "" {
+		return nil, errors.New("contract name is required")
+	}
+	if entryPoint == "" {
+		return nil, errors.New("entry point is required")
+	}
+	return &StoredContractByName{
+		tag:        tagStoredContractByName,
+		name:       name,
+		entryPoint: entryPoint,
+		args:       args,
+	}, nil
+}
+
+// NewStoredVersionedContractByHash calls the contract package stored under the 32 bytes package hash,
+// the latest version is used if version is nil
+func NewStoredVersionedContractByHash(hash []byte, version *uint32, entryPoint string, args RuntimeArgs) (*StoredVersionedContractByHash, error) {
+	if len(hash) != hashLen {
+		return nil, fmt.Errorf("invalid contract hash len: %d", len(hash))
+	}
+	if entryPoint == "" {
+		return nil, errors.New("entry point is required")
+	}
+	return &StoredVersionedContractByHash{
+		tag:        tagStoredVersionedContractByHash,
+		hash:       copyBytes(hash),
+		version:    copyVersion(version),
+		entryPoint: entryPoint,
+		args:       args,
+	}, nil
+}
+
+// NewStoredVersionedContractByName calls the contract package stored under the named key of the caller,
+// the latest version is used if version is nil
+func NewStoredVersionedContractByName(name string, version *uint32, entryPoint string, args RuntimeArgs) (*StoredVersionedContractByName, error) {
+	if name == "" {
+		return nil, errors.New("contract name is required")
+	}
+	if entryPoint == "" {
+		return nil, errors.New("entry point is required")
+	}
+	return &StoredVersionedContractByName{
+		tag:        tagStoredVersionedContractByName,
+		name:       name,
+		version:    copyVersion(version),
+		entryPoint: entryPoint,
+		args:       args,
+	}, nil
+}
+
+func copyBytes(b []byte) []byte {
+	return append([]byte{}, b...)
+}
+
+func copyVersion(version *uint32) *uint32 {
+	if version == nil {
+		return nil
+	}
+	v := *version
+	return &v
+}
+
+func (m *ModuleBytes) Args() RuntimeArgs {
+	return m.args
+}
+
+func (m *ModuleBytes) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		cl.ToBytesU8(uint8(m.tag)),
+		cl.ToBytesArrayU8(m.moduleBytes),
+		m.args.ToBytes(),
+	}, []byte{})
+}
+
+func (m *ModuleBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"ModuleBytes": struct {
+			ModuleBytes string      `json:"module_bytes"`
+			Args        RuntimeArgs `json:"args"`
+		}{hex.EncodeToString(m.moduleBytes), m.args},
+	})
+}
+
+func (s *StoredContractByHash) Args() RuntimeArgs {
+	return s.args
+}
+
+func (s *StoredContractByHash) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		cl.ToBytesU8(uint8(s.tag)),
+		s.hash,
+		cl.ToBytesString(s.entryPoint),
+		s.args.ToBytes(),
+	}, []byte{})
+}
+
+func (s *StoredContractByHash) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"StoredContractByHash": struct {
+			Hash       string      `json:"hash"`
+			EntryPoint string      `json:"entry_point"`
+			Args       RuntimeArgs `json:"args"`
+		}{hex.EncodeToString(s.hash), s.entryPoint, s.args},
+	})
+}
+
+func (s *StoredContractByName) Args() RuntimeArgs {
+	return s.args
+}
+
+func (s *StoredContractByName) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		cl.ToBytesU8(uint8(s.tag)),
+		cl.ToBytesString(s.name),
+		cl.ToBytesString(s.entryPoint),
+		s.args.ToBytes(),
+	}, []byte{})
+}
+
+func (s *StoredContractByName) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"StoredContractByName": struct {
+			Name       string      `json:"name"`
+			EntryPoint string      `json:"entry_point"`
+			Args       RuntimeArgs `json:"args"`
+		}{s.name, s.entryPoint, s.args},
+	})
+}
+
+func (s *StoredVersionedContractByHash) Args() RuntimeArgs {
+	return s.args
+}
+
+func (s *StoredVersionedContractByHash) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		cl.ToBytesU8(uint8(s.tag)),
+		s.hash,
+		versionToBytes(s.version),
+		cl.ToBytesString(s.entryPoint),
+		s.args.ToBytes(),
+	}, []byte{})
+}
+
+func (s *StoredVersionedContractByHash) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"StoredVersionedContractByHash": struct {
+			Hash       string      `json:"hash"`
+			Version    *uint32     `json:"version"`
+			EntryPoint string      `json:"entry_point"`
+			Args       RuntimeArgs `json:"args"`
+		}{hex.EncodeToString(s.hash), s.version, s.entryPoint, s.args},
+	})
+}
+
+func (s *StoredVersionedContractByName) Args() RuntimeArgs {
+	return s.args
+}
+
+func (s *StoredVersionedContractByName) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		cl.ToBytesU8(uint8(s.tag)),
+		cl.ToBytesString(s.name),
+		versionToBytes(s.version),
+		cl.ToBytesString(s.entryPoint),
+		s.args.ToBytes(),
+	}, []byte{})
+}
+
+func (s *StoredVersionedContractByName) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"StoredVersionedContractByName": struct {
+			Name       string      `json:"name"`
+			Version    *uint32     `json:"version"`
+			EntryPoint string      `json:"entry_point"`
+			Args       RuntimeArgs `json:"args"`
+		}{s.name, s.version, s.entryPoint, s.args},
+	})
+}
+
+func (t *Transfer) Args() RuntimeArgs {
+	return t.args
+}
+
+func (t *Transfer) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		cl.ToBytesU8(uint8(t.tag)),
+		t.args.ToBytes(),
+	}, []byte{})
+}
+
+func (t *Transfer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"Transfer": struct {
+			Args RuntimeArgs `json:"args"`
+		}{t.args},
+	})
+}
+
+// versionToBytes serializes the version as Option<u32>
+func versionToBytes(version *uint32) []byte {
+	if version == nil {
+		return []byte{0}
+	}
+	return append([]byte{1}, cl.ToBytesU32(*version)...)
+}
+
+// deployItemJson holds the fields of all the variants
+type deployItemJson struct {
+	ModuleBytes *string     `json:"module_bytes"`
+	Hash        string      `json:"hash"`
+	Name        string      `json:"name"`
+	Version     *uint32     `json:"version"`
+	EntryPoint  string      `json:"entry_point"`
+	Args        RuntimeArgs `json:"args"`
+}
+
+// unmarshalDeployItem decodes {"VariantName": {...}}
+func unmarshalDeployItem(data []byte) (ExecutableDeployItem, error) {
+	var variants map[string]*deployItemJson
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return nil, err
+	}
+	if len(variants) != 1 {
+		return nil, fmt.Errorf("deploy item must have exactly one variant, got %d", len(variants))
+	}
+	for variant, v := range variants {
+		if v == nil {
+			return nil, fmt.Errorf("%s is null", variant)
+		}
+		switch variant {
+		case "ModuleBytes":
+			if v.ModuleBytes == nil {
+				return nil, errors.New("module_bytes is required")
+			}
+			moduleBytes, err := hex.DecodeString(*v.ModuleBytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid module_bytes: %v", err)
+			}
+			return NewModuleBytes(moduleBytes, v.Args), nil
+		case "StoredContractByHash":
+			hash, err := hex.DecodeString(v.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("invalid contract hash: %v", err)
+			}
+			return NewStoredContractByHash(hash, v.EntryPoint, v.Args)
+		case "StoredContractByName":
+			return NewStoredContractByName(v.Name, v.EntryPoint, v.Args)
+		case "StoredVersionedContractByHash":
+			hash, err := hex.DecodeString(v.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("invalid contract package hash: %v", err)
+			}
+			return NewStoredVersionedContractByHash(hash, v.Version, v.EntryPoint, v.Args)
+		case "StoredVersionedContractByName":
+			return NewStoredVersionedContractByName(v.Name, v.Version, v.EntryPoint, v.Args)
+		case "Transfer":
+			return &Transfer{tag: tagTransfer, args: v.Args}, nil
+		}
+		return nil, fmt.Errorf("unknown deploy item: %s", variant)
+	}
+	return nil, nil
+}
@@ -1,7 +1,235 @@
 package deploy
 
-import "testing"
+import (
+	"encoding/hex"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"math/big"
+	"strings"
+	"testing"
+)
 
+// vectors follow the casper serialization standard
 func TestDeployItem(t *testing.T) {
+	hash, _ := hex.DecodeString(strings.Repeat("11", 32))
+	payment, _ := StandardPayment(big.NewInt(2500000000))
+	amountArgs := payment.args
+	version := uint32(2)
+	const (
+		amountArg   = "01000000" + "06000000616d6f756e74" + "050000000400f9029508"
+		transferHex = "080000007472616e73666572"
+		noArgs      = "00000000"
+	)
+	cases := []struct {
+		name   string
+		item   ExecutableDeployItem
+		expect string
+	}{
+		{
+			"ModuleBytes",
+			NewModuleBytes([]byte{0x00, 0x61, 0x73, 0x6d}, RuntimeArgs{}),
+			"00" + "040000000061736d" + noArgs,
+		},
+		{
+			"StandardPayment",
+			payment,
+			"00" + "00000000" + amountArg,
+		},
+		{
+			"StoredContractByHash",
+			&StoredContractByHash{tag: tagStoredContractByHash, hash: hash, entryPoint: "transfer", args: amountArgs},
+			"01" + strings.Repeat("11", 32) + transferHex + amountArg,
+		},
+		{
+			"StoredContractByName",
+			&StoredContractByName{tag: tagStoredContractByName, name: "faucet", entryPoint: "call"},
+			"02" + "06000000666175636574" + "0400000063616c6c" + noArgs,
+		},
+		{
+			"StoredVersionedContractByHash",
+			&StoredVersionedContractByHash{tag: tagStoredVersionedContractByHash, hash: hash, entryPoint: "transfer", args: amountArgs},
+			"03" + strings.Repeat("11", 32) + "00" + transferHex + amountArg,
+		},
+		{
+			"StoredVersionedContractByName",
+			&StoredVersionedContractByName{tag: tagStoredVersionedContractByName, name: "faucet", version: &version, entryPoint: "call"},
+			"04" + "06000000666175636574" + "0102000000" + "0400000063616c6c" + noArgs,
+		},
+		{
+			"Transfer",
+			&Transfer{tag: tagTransfer, args: amountArgs},
+			"05" + amountArg,
+		},
+	}
+	for _, c := range cases {
+		if actual := hex.EncodeToString(c.item.ToBytes()); actual != c.expect {
+			t.Errorf("%s bytes error,\nexpect: %s\nactual: %s", c.name, c.expect, actual)
+		}
+	}
+}
+
+// the example deploy of the casper-node json-rpc schema(account_put_deploy), built from scratch
+func TestDeploy_NodeVector(t *testing.T) {
+	const (
+		bodyHash   = "d53cf72d17278fd47d399013ca389c50d589352f1a12593c0b8e01872a641b50"
+		deployHash = "5c9b3b099c1378aa8e4a5f07f59ff1fcdc69a83179427c7e67ae0377d94d93fa"
+		headerHex  = "01d9bf2148748a85c89da5aad8ee0b0fc2d105fd39d41a4c796536354f0ae2900c" + "a856a4d375010000" + "80ee360000000000" +
+			"0100000000000000" + bodyHash + "01000000" + "0101010101010101010101010101010101010101010101010101010101010101" +
+			"0e0000006361737065722d6578616d706c65"
+		amountArg = "01000000" + "06000000616d6f756e74" + "04000000e8030000" + "01"
+	)
+	account, _ := cl.ParsePublicKey("01d9bf2148748a85c89da5aad8ee0b0fc2d105fd39d41a4c796536354f0ae2900c")
+	amount, _ := cl.NewI32(1000)
+	var args RuntimeArgs
+	if err := args.Insert("amount", amount); err != nil {
+		t.Fatal(err)
+	}
+	payment, err := NewStoredContractByName("casper-example", "example-entry-point", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &Transfer{tag: tagTransfer, args: args}
+	dep, _ := hex.DecodeString(strings.Repeat("01", 32))
+	p := NewDeployParam(account, "casper-example").
+		SetTimestamp(1605573564072).
+		SetTTL(60 * 60 * 1000).
+		SetGasPrice(1).
+		SetDependencies([][]byte{dep})
+	d, err := MakeDeploy(p, session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := hex.EncodeToString(payment.ToBytes()); actual != "02"+"0e0000006361737065722d6578616d706c65"+"130000006578616d706c652d656e7472792d706f696e74"+amountArg {
+		t.Fatalf("payment bytes error: %s", actual)
+	}
+	if actual := hex.EncodeToString(session.ToBytes()); actual != "05"+amountArg {
+		t.Fatalf("session bytes error: %s", actual)
+	}
+	if actual := hex.EncodeToString(d.Header.ToBytes()); actual != headerHex {
+		t.Fatalf("header bytes error: %s", actual)
+	}
+	if hex.EncodeToString(d.Header.BodyHash) != bodyHash || hex.EncodeToString(d.Hash) != deployHash {
+		t.Fatalf("hash error: %x %x", d.Header.BodyHash, d.Hash)
+	}
+	sig, _ := hex.DecodeString("014c1a89f92e29dd74fc648f741137d9caf4edba97c5f9799ce0c9aa6b0c9b58db368c64098603dbecef645774c05dff057cb1f91f2cf390bbacce78aa6f084007")
+	if err = d.AddApproval(account, sig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRuntimeArgs_Order(t *testing.T) {
+	target, _ := hex.DecodeString(strings.Repeat("22", 32))
+	_, err := NewTransfer(big.NewInt(1), nil, 7)
+	if err == nil {
+		t.Fatal("nil target should be rejected")
+	}
+	session, err := NewTransfer(big.NewInt(1), cl.NewByteArray(target), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := session.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(data)
+	if !(strings.Index(s, `"amount"`) < strings.Index(s, `"target"`) && strings.Index(s, `"target"`) < strings.Index(s, `"id"`)) {
+		t.Fatalf("args order error: %s", s)
+	}
+}
+
+func TestDeployItem_JSON(t *testing.T) {
+	hash, _ := hex.DecodeString(strings.Repeat("11", 32))
+	payment, _ := StandardPayment(big.NewInt(2500000000))
+	version := uint32(2)
+	items := []ExecutableDeployItem{
+		NewModuleBytes([]byte{0x00, 0x61, 0x73, 0x6d}, RuntimeArgs{}),
+		payment,
+		&StoredContractByHash{tag: tagStoredContractByHash, hash: hash, entryPoint: "transfer", args: payment.args},
+		&StoredContractByName{tag: tagStoredContractByName, name: "faucet", entryPoint: "call"},
+		&StoredVersionedContractByHash{tag: tagStoredVersionedContractByHash, hash: hash, entryPoint: "transfer", args: payment.args},
+		&StoredVersionedContractByName{tag: tagStoredVersionedContractByName, name: "faucet", version: &version, entryPoint: "call"},
+		&Transfer{tag: tagTransfer, args: payment.args},
+	}
+	for _, item := range items {
+		data, err := item.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := unmarshalDeployItem(data)
+		if err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		if hex.EncodeToString(decoded.ToBytes()) != hex.EncodeToString(item.ToBytes()) {
+			t.Fatalf("json round trip error: %s", data)
+		}
+	}
 
+	invalid := []string{
+		`{}`,
+		`{"ModuleBytes":{"args":[]},"Transfer":{"args":[]}}`,
+		`{"ModuleBytes":{"args":[]}}`,
+		`{"StoredContractByHash":{"hash":"1111","entry_point":"call","args":[]}}`,
+		`{"Unknown":{"args":[]}}`,
+	}
+	for _, s := range invalid {
+		if _, err := unmarshalDeployItem([]byte(s)); err == nil {
+			t.Fatalf("%s should be rejected", s)
+		}
+	}
 }
+
+func TestNewStoredContract(t *testing.T) {
+	hash, _ := hex.DecodeString(strings.Repeat("11", 32))
+	recipient, _ := cl.ParseKey("account-hash-" + strings.Repeat("22", 32))
+	amount, _ := cl.NewU256(big.NewInt(1000))
+	var args RuntimeArgs
+	_ = args.Insert("recipient", recipient)
+	_ = args.Insert("amount", amount)
+	const cep18Args = "02000000" +
+		"09000000726563697069656e74" + "21000000" + "00" + "2222222222222222222222222222222222222222222222222222222222222222" + "0b" +
+		"06000000616d6f756e74" + "0300000002e80307"
+	version := uint32(1)
+
+	byHash, err := NewStoredContractByHash(hash, "transfer", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName, err := NewStoredContractByName("cep18_contract_hash", "transfer", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionedByHash, err := NewStoredVersionedContractByHash(hash, nil, "transfer", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionedByName, err := NewStoredVersionedContractByName("cep18_package", &version, "transfer", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	version = 5
+	cases := []struct {
+		item   ExecutableDeployItem
+		expect string
+	}{
+		{byHash, "01" + strings.Repeat("11", 32) + transferEntryPoint + cep18Args},
+		{byName, "02" + "1300000063657031385f636f6e74726163745f68617368" + transferEntryPoint + cep18Args},
+		{versionedByHash, "03" + strings.Repeat("11", 32) + "00" + transferEntryPoint + cep18Args},
+		{versionedByName, "04" + "0d00000063657031385f7061636b616765" + "0101000000" + transferEntryPoint + cep18Args},
+	}
+	for _, c := range cases {
+		if actual := hex.EncodeToString(c.item.ToBytes()); actual != c.expect {
+			t.Errorf("bytes error,\nexpect: %s\nactual: %s", c.expect, actual)
+		}
+	}
+
+	if _, err = NewStoredContractByHash(hash[:31], "transfer", args); err == nil {
+		t.Fatal("invalid hash should be rejected")
+	}
+	if _, err = NewStoredContractByName("", "transfer", args); err == nil {
+		t.Fatal("empty name should be rejected")
+	}
+	if _, err = NewStoredVersionedContractByHash(hash, nil, "", args); err == nil {
+		t.Fatal("empty entry point should be rejected")
+	}
+}
+
+const transferEntryPoint = "080000007472616e73666572"
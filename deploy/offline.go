@@ -0,0 +1,62 @@
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"io/ioutil"
+)
+
+// WriteFile exports the deploy as json, e.g. an unsigned deploy carried to an offline signer
+func (d *Deploy) WriteFile(path string) error {
+	if err := d.CheckHash(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// ReadFile imports the deploy json written by WriteFile or by casper-client,
+// the hashes and the signatures of the approvals are checked
+func ReadFile(path string) (*Deploy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d, err := ParseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode deploy %s error: %v", path, err)
+	}
+	if err = d.CheckHash(); err != nil {
+		return nil, err
+	}
+	if err = d.VerifyApprovals(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// CheckSigners checks the deploy is approved and every approval is signed by the header account
+// or by one of its associated keys, the associated keys can be queried with state_get_item
+func (d *Deploy) CheckSigners(associatedKeys ...cl.AccountHash) error {
+	if len(d.Approvals) == 0 {
+		return errors.New("deploy is not approved")
+	}
+	if err := d.VerifyApprovals(); err != nil {
+		return err
+	}
+	allowed := map[cl.AccountHash]bool{d.Header.Account.AccountHash(): true}
+	for _, ah := range associatedKeys {
+		allowed[ah] = true
+	}
+	for _, a := range d.Approvals {
+		if !allowed[a.Signer.AccountHash()] {
+			return fmt.Errorf("%w: %s is not an associated key of %s", ErrInvalidApproval, a.Signer, d.Header.Account)
+		}
+	}
+	return nil
+}
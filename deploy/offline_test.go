@@ -0,0 +1,75 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"github.com/JFJun/casperlabs-go/keys"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOfflineSigning(t *testing.T) {
+	priv1, pub1, _ := keys.GenerateKeys(keys.Ed25519)
+	priv2, _ := hex.DecodeString("be798eee9bb3fa267e0525a7633260c5d2a9512dd2f96b8d621f560dd233d99a")
+	pub2, _ := hex.DecodeString("03447239548b66bdfe334131392dd9db386c054989e2b815fe68fd634c9e4703a1")
+	account, _ := cl.NewPublicKey(cl.Ed25519Tag, pub1)
+	cosigner, _ := cl.NewPublicKey(cl.Secp256K1Tag, pub2)
+	session, _ := NewTransfer(big.NewInt(2500000000), cosigner, 7)
+	payment, _ := StandardPayment(big.NewInt(10000))
+	d, err := MakeDeploy(NewDeployParam(account, "casper-test").SetTTL(60*60*1000), session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	unsigned := filepath.Join(dir, "unsigned.json")
+	if err = d.WriteFile(unsigned); err != nil {
+		t.Fatal(err)
+	}
+
+	// offline host: import, check the hash and sign with both keys
+	offline, err := ReadFile(unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(offline.Hash, d.Hash) || !bytes.Equal(offline.ToBytes(), d.ToBytes()) {
+		t.Fatal("deploy changed after import")
+	}
+	if err = offline.Sign(keys.NewKeyHolder(priv1, pub1, keys.Ed25519)); err != nil {
+		t.Fatal(err)
+	}
+	if err = offline.Sign(keys.NewKeyHolder(priv2, pub2, keys.Secp256K1)); err != nil {
+		t.Fatal(err)
+	}
+	signed := filepath.Join(dir, "signed.json")
+	if err = offline.WriteFile(signed); err != nil {
+		t.Fatal(err)
+	}
+
+	// online host: import the signed deploy before broadcast
+	back, err := ReadFile(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(back.Hash, d.Hash) || len(back.Approvals) != 2 {
+		t.Fatal("signed deploy error")
+	}
+	if err = back.CheckSigners(); err == nil {
+		t.Fatal("cosigner is not an associated key")
+	}
+	if err = back.CheckSigners(cosigner.AccountHash()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the deploy must not be changed on the way
+	data, _ := ioutil.ReadFile(signed)
+	tampered := filepath.Join(dir, "tampered.json")
+	_ = ioutil.WriteFile(tampered, []byte(strings.Replace(string(data), `"ttl": "1h"`, `"ttl": "2h"`, 1)), 0644)
+	if _, err = ReadFile(tampered); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("tampered deploy should be rejected: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+)
+
+type NamedArg struct {
+	name  string
+	value *cl.CLValue
+}
+
+// RuntimeArgs keeps the args in insertion order, the order is part of the serialized bytes.
+// The zero value is an empty args list ready to use.
+type RuntimeArgs struct {
+	args []*NamedArg
+}
+
+func newNamedArg(name string, value cl.CLTypedAndToBytes) *NamedArg {
+	return &NamedArg{
+		name:  name,
+		value: cl.NewCLValue(value),
+	}
+}
+
+func (na *NamedArg) Name() string {
+	return na.name
+}
+
+func (na *NamedArg) Value() *cl.CLValue {
+	return na.value
+}
+
+func (na *NamedArg) ToBytes() []byte {
+	return bytes.Join([][]byte{
+		cl.ToBytesString(na.name),
+		na.value.ToBytes(),
+	}, []byte{})
+}
+
+// MarshalJSON encodes the arg as [name, clvalue]
+func (na *NamedArg) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{na.name, na.value})
+}
+
+func (na *NamedArg) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("named arg must be [name, clvalue], got %d items", len(pair))
+	}
+	var name string
+	if err := json.Unmarshal(pair[0], &name); err != nil {
+		return err
+	}
+	value := &cl.CLValue{}
+	if err := json.Unmarshal(pair[1], value); err != nil {
+		return fmt.Errorf("arg %s: %v", name, err)
+	}
+	na.name = name
+	na.value = value
+	return nil
+}
+
+// Insert appends the arg, duplicate names are rejected
+func (ra *RuntimeArgs) Insert(name string, value cl.CLTypedAndToBytes) error {
+	if value == nil {
+		return fmt.Errorf("arg %s: value is nil", name)
+	}
+	return ra.insert(newNamedArg(name, value))
+}
+
+// InsertCLValue appends an already built CLValue, e.g. one decoded from bytes or json
+func (ra *RuntimeArgs) InsertCLValue(name string, value *cl.CLValue) error {
+	if value == nil {
+		return fmt.Errorf("arg %s: value is nil", name)
+	}
+	return ra.insert(&NamedArg{name: name, value: value})
+}
+
+func (ra *RuntimeArgs) insert(arg *NamedArg) error {
+	if ra.Get(arg.name) != nil {
+		return fmt.Errorf("duplicate arg name: %s", arg.name)
+	}
+	ra.args = append(ra.args, arg)
+	return nil
+}
+
+// Get returns the value of the arg, nil if not found
+func (ra RuntimeArgs) Get(name string) *cl.CLValue {
+	for _, arg := range ra.args {
+		if arg.name == name {
+			return arg.value
+		}
+	}
+	return nil
+}
+
+// Names returns the arg names in insertion order
+func (ra RuntimeArgs) Names() []string {
+	names := make([]string, 0, len(ra.args))
+	for _, arg := range ra.args {
+		names = append(names, arg.name)
+	}
+	return names
+}
+
+func (ra RuntimeArgs) Len() int {
+	return len(ra.args)
+}
+
+func (ra RuntimeArgs) ToBytes() []byte {
+	buf := cl.ToBytesU32(uint32(len(ra.args)))
+	for _, arg := range ra.args {
+		buf = append(buf, arg.ToBytes()...)
+	}
+	return buf
+}
+
+func (ra RuntimeArgs) MarshalJSON() ([]byte, error) {
+	if ra.args == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(ra.args)
+}
+
+// UnmarshalJSON decodes [[name, clvalue], ...], the order of the list is kept
+func (ra *RuntimeArgs) UnmarshalJSON(data []byte) error {
+	var args []*NamedArg
+	if err := json.Unmarshal(data, &args); err != nil {
+		return err
+	}
+	decoded := RuntimeArgs{args: []*NamedArg{}}
+	for _, arg := range args {
+		if arg == nil {
+			return errors.New("named arg is null")
+		}
+		if err := decoded.insert(arg); err != nil {
+			return err
+		}
+	}
+	*ra = decoded
+	return nil
+}
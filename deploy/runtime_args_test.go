@@ -0,0 +1,65 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestRuntimeArgs_Insert(t *testing.T) {
+	var ra RuntimeArgs
+	amount, _ := cl.NewU512(big.NewInt(2500000000))
+	if err := ra.Insert("amount", amount); err != nil {
+		t.Fatal(err)
+	}
+	if err := ra.Insert("target", cl.NewString("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ra.Insert("amount", cl.NewString("again")); err == nil {
+		t.Fatal("duplicate name should be rejected")
+	}
+	if err := ra.Insert("nil", nil); err == nil {
+		t.Fatal("nil value should be rejected")
+	}
+	if !reflect.DeepEqual(ra.Names(), []string{"amount", "target"}) {
+		t.Fatalf("names error: %v", ra.Names())
+	}
+	if ra.Get("target").Value().(*cl.String).Value() != "alice" || ra.Get("missing") != nil {
+		t.Fatal("get error")
+	}
+}
+
+func TestRuntimeArgs_JSON(t *testing.T) {
+	var ra RuntimeArgs
+	id, _ := cl.NewU64(7)
+	amount, _ := cl.NewU512(big.NewInt(1000))
+	_ = ra.Insert("target", cl.NewByteArray(bytes.Repeat([]byte{0x22}, 32)))
+	_ = ra.Insert("amount", amount)
+	_ = ra.Insert("id", cl.NewSomeOption(id))
+	data, err := json.Marshal(ra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded RuntimeArgs
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.ToBytes(), ra.ToBytes()) {
+		t.Fatalf("json round trip error: %s", data)
+	}
+	if !reflect.DeepEqual(decoded.Names(), []string{"target", "amount", "id"}) {
+		t.Fatalf("order error: %v", decoded.Names())
+	}
+
+	duplicate := `[["a",{"cl_type":"U8","bytes":"01"}],["a",{"cl_type":"U8","bytes":"02"}]]`
+	if err := json.Unmarshal([]byte(duplicate), &decoded); err == nil {
+		t.Fatal("duplicate name should be rejected")
+	}
+	invalid := `[["a",{"cl_type":"U8","bytes":"0102"}]]`
+	if err := json.Unmarshal([]byte(invalid), &decoded); err == nil {
+		t.Fatal("invalid bytes should be rejected")
+	}
+}
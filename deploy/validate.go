@@ -0,0 +1,130 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"time"
+)
+
+// DefaultMaxDeploySize is the max serialized deploy size of mainnet and testnet, 1 MiB
+const DefaultMaxDeploySize = 1024 * 1024
+
+var ErrInvalidDeploy = errors.New("invalid deploy")
+
+// Chainspec holds the deploy limits of the network, see the [deploys] section of chainspec.toml
+type Chainspec struct {
+	ChainName string
+	// max time to live in milliseconds
+	MaxTTL uint64
+	// max serialized deploy size in bytes
+	MaxDeploySize   int
+	MaxDependencies int
+	// allowed clock drift of the deploy timestamp in milliseconds
+	TimestampLeeway uint64
+}
+
+// DefaultChainspec returns the limits used by mainnet and testnet
+func DefaultChainspec(chainName string) *Chainspec {
+	return &Chainspec{
+		ChainName:       chainName,
+		MaxTTL:          24 * 60 * 60 * 1000,
+		MaxDeploySize:   DefaultMaxDeploySize,
+		MaxDependencies: 10,
+		TimestampLeeway: 5 * 1000,
+	}
+}
+
+// Validate checks the deploy against the chainspec before sending it,
+// the approvals are not checked, use VerifyApprovals.
+// DefaultChainspec is used if spec is nil, the chain name is not checked then
+func (d *Deploy) Validate(spec *Chainspec) error {
+	if err := d.CheckHash(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDeploy, err)
+	}
+	if spec == nil {
+		spec = DefaultChainspec(d.Header.ChainName)
+	}
+	if err := d.Header.validate(spec, uint64(time.Now().UnixNano()/int64(time.Millisecond))); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDeploy, err)
+	}
+	if size := len(d.ToBytes()); size > spec.MaxDeploySize {
+		return fmt.Errorf("%w: deploy size %d exceeds the limit %d", ErrInvalidDeploy, size, spec.MaxDeploySize)
+	}
+	if err := validateArgs(d.Payment.Args()); err != nil {
+		return fmt.Errorf("%w: payment %v", ErrInvalidDeploy, err)
+	}
+	if err := validateArgs(d.Session.Args()); err != nil {
+		return fmt.Errorf("%w: session %v", ErrInvalidDeploy, err)
+	}
+	if err := validatePaymentAmount(d.Payment); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDeploy, err)
+	}
+	return nil
+}
+
+// validate checks the header fields, now is the unix timestamp in milliseconds
+func (h *DeployHeader) validate(spec *Chainspec, now uint64) error {
+	if h.ChainName != spec.ChainName {
+		return fmt.Errorf("chain name %s does not match %s", h.ChainName, spec.ChainName)
+	}
+	if h.TTL == 0 || h.TTL > spec.MaxTTL {
+		return fmt.Errorf("ttl %s is out of range, max is %s", formatTTL(h.TTL), formatTTL(spec.MaxTTL))
+	}
+	// compare the differences, timestamp+ttl may overflow
+	if h.Timestamp > now && h.Timestamp-now > spec.TimestampLeeway {
+		return fmt.Errorf("timestamp %s is in the future", formatTimestamp(h.Timestamp))
+	}
+	if h.Timestamp <= now && now-h.Timestamp > h.TTL {
+		return fmt.Errorf("deploy expired at %s", formatTimestamp(h.Timestamp+h.TTL))
+	}
+	if h.GasPrice < DefaultGasPrice {
+		return fmt.Errorf("gas price must be at least %d", DefaultGasPrice)
+	}
+	if len(h.Dependencies) > spec.MaxDependencies {
+		return fmt.Errorf("%d dependencies exceed the limit %d", len(h.Dependencies), spec.MaxDependencies)
+	}
+	for _, dep := range h.Dependencies {
+		if len(dep) != hashLen {
+			return fmt.Errorf("invalid dependency len: %d", len(dep))
+		}
+	}
+	return nil
+}
+
+// validateArgs checks every value can be decoded with its cl type
+func validateArgs(ra RuntimeArgs) error {
+	names := make(map[string]bool)
+	for _, arg := range ra.args {
+		if arg.value == nil {
+			return fmt.Errorf("arg %s is nil", arg.name)
+		}
+		if names[arg.name] {
+			return fmt.Errorf("duplicate arg name: %s", arg.name)
+		}
+		names[arg.name] = true
+		if _, err := cl.FromBytes(arg.value.Bytes(), arg.value.CLType()); err != nil {
+			return fmt.Errorf("arg %s: %v", arg.name, err)
+		}
+	}
+	return nil
+}
+
+// validatePaymentAmount checks the amount arg, it is required by the standard payment(empty module bytes)
+func validatePaymentAmount(payment ExecutableDeployItem) error {
+	amount := payment.Args().Get("amount")
+	if amount == nil {
+		if m, ok := payment.(*ModuleBytes); ok && len(m.moduleBytes) == 0 {
+			return errors.New("standard payment requires the amount arg")
+		}
+		return nil
+	}
+	n, ok := amount.Value().(cl.BigNumber)
+	if !ok {
+		return fmt.Errorf("payment amount must be a number, got %s", amount.CLType())
+	}
+	if n.Value().Sign() <= 0 {
+		return errors.New("payment amount must be greater than zero")
+	}
+	return nil
+}
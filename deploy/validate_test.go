@@ -0,0 +1,95 @@
+package deploy
+
+import (
+	"errors"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDeploy_Validate(t *testing.T) {
+	account, _ := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	session, _ := NewTransfer(big.NewInt(2500000000), account, 1)
+	now := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	spec := DefaultChainspec("casper-test")
+	makeDeploy := func(p *DeployParam, paymentAmount int64) *Deploy {
+		payment, _ := StandardPayment(big.NewInt(paymentAmount))
+		d, err := MakeDeploy(p, session, payment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	if err := makeDeploy(NewDeployParam(account, "casper-test"), 10000).Validate(spec); err != nil {
+		t.Fatal(err)
+	}
+	// nil falls back to the default limits
+	if err := makeDeploy(NewDeployParam(account, "casper-test"), 10000).Validate(nil); err != nil {
+		t.Fatal(err)
+	}
+	expired := makeDeploy(NewDeployParam(account, "casper-test").SetTimestamp(now-2*DefaultTTL), 10000)
+	if err := expired.Validate(nil); !errors.Is(err, ErrInvalidDeploy) {
+		t.Fatalf("expired deploy should be rejected with nil spec: %v", err)
+	}
+
+	deps := make([][]byte, 11)
+	for i := range deps {
+		deps[i] = make([]byte, 32)
+	}
+	tampered := makeDeploy(NewDeployParam(account, "casper-test"), 10000)
+	tampered.Header.GasPrice = 2
+	small := DefaultChainspec("casper-test")
+	small.MaxDeploySize = 100
+	cases := []struct {
+		name string
+		d    *Deploy
+		spec *Chainspec
+	}{
+		{"chain name", makeDeploy(NewDeployParam(account, "casper"), 10000), spec},
+		{"ttl", makeDeploy(NewDeployParam(account, "casper-test").SetTTL(spec.MaxTTL+1), 10000), spec},
+		{"future", makeDeploy(NewDeployParam(account, "casper-test").SetTimestamp(now+60*1000), 10000), spec},
+		{"expired", makeDeploy(NewDeployParam(account, "casper-test").SetTimestamp(now-2*DefaultTTL), 10000), spec},
+		{"gas price", makeDeploy(NewDeployParam(account, "casper-test").SetGasPrice(0), 10000), spec},
+		{"dependencies", makeDeploy(NewDeployParam(account, "casper-test").SetDependencies(deps), 10000), spec},
+		{"payment amount", makeDeploy(NewDeployParam(account, "casper-test"), 0), spec},
+		{"hash", tampered, spec},
+		{"size", makeDeploy(NewDeployParam(account, "casper-test"), 10000), small},
+	}
+	for _, c := range cases {
+		if err := c.d.Validate(c.spec); !errors.Is(err, ErrInvalidDeploy) {
+			t.Errorf("%s: expect invalid deploy, got %v", c.name, err)
+		}
+	}
+
+	noAmount := makeDeploy(NewDeployParam(account, "casper-test"), 10000)
+	noAmount.Payment = NewModuleBytes([]byte{}, RuntimeArgs{})
+	noAmount.Header.BodyHash = noAmount.ComputeBodyHash()
+	noAmount.Hash = noAmount.Header.ComputeHash()
+	if err := noAmount.Validate(spec); !errors.Is(err, ErrInvalidDeploy) {
+		t.Fatalf("standard payment without amount should be rejected: %v", err)
+	}
+}
+
+func TestDeployHeader_validate_Overflow(t *testing.T) {
+	account, _ := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	spec := DefaultChainspec("casper-test")
+	spec.MaxTTL = math.MaxUint64
+	now := uint64(1615364499062)
+	header := func(timestamp, ttl uint64) *DeployHeader {
+		return &DeployHeader{Account: account, Timestamp: timestamp, TTL: ttl, GasPrice: 1, ChainName: "casper-test"}
+	}
+	if err := header(now-2*DefaultTTL, math.MaxUint64).validate(spec, now); err != nil {
+		t.Fatalf("max ttl should not expire: %v", err)
+	}
+	if err := header(now+spec.TimestampLeeway, math.MaxUint64).validate(spec, now); err != nil {
+		t.Fatalf("timestamp within the leeway should be accepted: %v", err)
+	}
+	if err := header(now-2*DefaultTTL, DefaultTTL).validate(spec, now); err == nil {
+		t.Fatal("expired deploy should be rejected")
+	}
+	if err := header(math.MaxUint64, DefaultTTL).validate(spec, now); err == nil {
+		t.Fatal("future deploy should be rejected")
+	}
+}
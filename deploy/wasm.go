@@ -0,0 +1,142 @@
+package deploy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const (
+	wasmExportSection = 7
+	wasmExportFunc    = 0
+	// the entry point of session code
+	wasmEntryPoint = "call"
+)
+
+var (
+	ErrInvalidWasm = errors.New("invalid wasm module")
+
+	wasmMagic   = []byte{0x00, 0x61, 0x73, 0x6d}
+	wasmVersion = []byte{0x01, 0x00, 0x00, 0x00}
+)
+
+// ModuleBytesFromFile loads the session wasm, see ModuleBytesFromReader
+func ModuleBytesFromFile(path string, args RuntimeArgs) (*ModuleBytes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := ModuleBytesFromReader(f, args)
+	if err != nil {
+		return nil, fmt.Errorf("load %s error: %w", path, err)
+	}
+	return m, nil
+}
+
+// ModuleBytesFromReader loads the session wasm and checks the magic, the version and the call export.
+// A module larger than DefaultMaxDeploySize can never be deployed and is rejected, the deploy built with
+// the module still has to be checked with Deploy.Validate, Size returns the module size
+func ModuleBytesFromReader(r io.Reader, args RuntimeArgs) (*ModuleBytes, error) {
+	moduleBytes, err := ioutil.ReadAll(io.LimitReader(r, DefaultMaxDeploySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(moduleBytes) > DefaultMaxDeploySize {
+		// count the rest to report the module size, the bytes are not kept
+		rest, err := io.Copy(ioutil.Discard, r)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: module size %d exceeds the max deploy size %d", ErrInvalidWasm, int64(len(moduleBytes))+rest, DefaultMaxDeploySize)
+	}
+	if err = checkWasm(moduleBytes); err != nil {
+		return nil, err
+	}
+	return NewModuleBytes(moduleBytes, args), nil
+}
+
+// Size returns the length of the module bytes
+func (m *ModuleBytes) Size() int {
+	return len(m.moduleBytes)
+}
+
+// checkWasm checks the header and the section framing, and looks for the call function in the export section
+func checkWasm(module []byte) error {
+	if len(module) < 8 || !bytes.Equal(module[:4], wasmMagic) {
+		return fmt.Errorf("%w: bad magic number", ErrInvalidWasm)
+	}
+	if !bytes.Equal(module[4:8], wasmVersion) {
+		return fmt.Errorf("%w: unsupported version %x", ErrInvalidWasm, module[4:8])
+	}
+	rest := module[8:]
+	exported := false
+	for len(rest) > 0 {
+		id := rest[0]
+		size, n, err := readLEB128(rest[1:])
+		if err != nil {
+			return err
+		}
+		rest = rest[1+n:]
+		if uint64(size) > uint64(len(rest)) {
+			return fmt.Errorf("%w: section %d is truncated", ErrInvalidWasm, id)
+		}
+		if id == wasmExportSection {
+			if exported, err = hasFuncExport(rest[:size], wasmEntryPoint); err != nil {
+				return err
+			}
+		}
+		rest = rest[size:]
+	}
+	if exported {
+		return nil
+	}
+	return fmt.Errorf("%w: %s function is not exported", ErrInvalidWasm, wasmEntryPoint)
+}
+
+func hasFuncExport(section []byte, name string) (bool, error) {
+	count, n, err := readLEB128(section)
+	if err != nil {
+		return false, err
+	}
+	rest := section[n:]
+	for i := uint32(0); i < count; i++ {
+		l, n, err := readLEB128(rest)
+		if err != nil {
+			return false, err
+		}
+		rest = rest[n:]
+		if uint64(l)+1 > uint64(len(rest)) {
+			return false, fmt.Errorf("%w: export section is truncated", ErrInvalidWasm)
+		}
+		exportName, kind := string(rest[:l]), rest[l]
+		rest = rest[l+1:]
+		if _, n, err = readLEB128(rest); err != nil {
+			return false, err
+		}
+		rest = rest[n:]
+		if exportName == name && kind == wasmExportFunc {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// readLEB128 reads an unsigned LEB128 u32, returns the value and the number of bytes read
+func readLEB128(data []byte) (uint32, int, error) {
+	var v uint32
+	for i := 0; i < 5; i++ {
+		if i >= len(data) {
+			return 0, 0, fmt.Errorf("%w: unexpected end of module", ErrInvalidWasm)
+		}
+		b := data[i]
+		v |= uint32(b&0x7f) << (7 * uint(i))
+		if b&0x80 == 0 {
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: integer too long", ErrInvalidWasm)
+}
@@ -0,0 +1,71 @@
+package deploy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// (module (func) (export "call" (func 0)))
+var testWasm = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x04, 0x01, 0x60, 0x00, 0x00,
+	0x03, 0x02, 0x01, 0x00,
+	0x07, 0x08, 0x01, 0x04, 'c', 'a', 'l', 'l', 0x00, 0x00,
+	0x0a, 0x04, 0x01, 0x02, 0x00, 0x0b,
+}
+
+func TestModuleBytesFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.wasm")
+	if err := ioutil.WriteFile(path, testWasm, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ModuleBytesFromFile(path, RuntimeArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m.moduleBytes, testWasm) {
+		t.Fatal("module bytes error")
+	}
+	if _, err = ModuleBytesFromFile(filepath.Join(t.TempDir(), "missing.wasm"), RuntimeArgs{}); err == nil {
+		t.Fatal("missing file should be rejected")
+	}
+}
+
+func TestModuleBytesFromReader(t *testing.T) {
+	replace := func(i int, b ...byte) []byte {
+		module := append([]byte{}, testWasm...)
+		copy(module[i:], b)
+		return module
+	}
+	exportMemory := replace(26, 0x02)
+	exportCell := replace(25, 'e')
+	tooLarge := append(append([]byte{}, testWasm...), make([]byte, DefaultMaxDeploySize)...)
+	cases := map[string][]byte{
+		"magic":     replace(0, 0x00, 0x61, 0x73, 0x6e),
+		"version":   replace(4, 0x02),
+		"empty":     {},
+		"truncated": testWasm[:len(testWasm)-2],
+		"memory":    exportMemory,
+		"name":      exportCell,
+		"no export": testWasm[:18],
+		"size":      tooLarge,
+	}
+	for name, module := range cases {
+		if _, err := ModuleBytesFromReader(bytes.NewReader(module), RuntimeArgs{}); !errors.Is(err, ErrInvalidWasm) {
+			t.Errorf("%s: expect invalid wasm, got %v", name, err)
+		}
+	}
+	_, err := ModuleBytesFromReader(bytes.NewReader(tooLarge), RuntimeArgs{})
+	if !strings.Contains(fmt.Sprint(err), fmt.Sprintf("module size %d exceeds", len(tooLarge))) {
+		t.Fatalf("size error: %v", err)
+	}
+	m, err := ModuleBytesFromReader(bytes.NewReader(testWasm), RuntimeArgs{})
+	if err != nil || m.Size() != len(testWasm) {
+		t.Fatalf("module size error: %v", err)
+	}
+}
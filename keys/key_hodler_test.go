@@ -1,6 +1,9 @@
 package keys
 
-import "testing"
+import (
+	"encoding/hex"
+	"testing"
+)
 
 func TestTIsAccount(t *testing.T) {
 	addr1 := "10123123123"
@@ -36,3 +39,28 @@ func TestTIsAccount(t *testing.T) {
 		t.Fatal("failed to test: secp256k1 addr")
 	}
 }
+
+func TestAddressToAccountHash(t *testing.T) {
+	cases := []struct {
+		address string
+		pub     string
+		sa      SignatureAlgorithm
+	}{
+		{"0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c", "78a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c", Ed25519},
+		{"0203447239548b66bdfe334131392dd9db386c054989e2b815fe68fd634c9e4703a1", "03447239548b66bdfe334131392dd9db386c054989e2b815fe68fd634c9e4703a1", Secp256K1},
+	}
+	for _, c := range cases {
+		fromAddress, err := AddressToAccountHash(c.address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, _ := hex.DecodeString(c.pub)
+		fromPub, err := AccountHash(pub, c.sa)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(fromAddress) != fromPub {
+			t.Fatalf("account hash mismatch: %x != %s", fromAddress, fromPub)
+		}
+	}
+}
@@ -1,12 +1,11 @@
 package keys
 
 import (
-	"bytes"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
 	"github.com/JFJun/casperlabs-go/common/hexutil"
-	"github.com/JFJun/casperlabs-go/keys/blake2b"
 )
 
 type KeyHolder interface {
@@ -85,12 +84,15 @@ func CheckPrivKey(priv []byte, l int) error {
 
 //根据公钥数据生成accountHash
 func AccountHash(pub []byte, sa SignatureAlgorithm) (string, error) {
-	msg := bytes.Join([][]byte{
-		[]byte(sa),
-		{0},
-		pub,
-	}, []byte{})
-	return hex.EncodeToString(blake2b.Hash(msg)), nil
+	tag, err := AlgorithmTag(sa)
+	if err != nil {
+		return "", err
+	}
+	pk, err := cl.NewPublicKey(tag, pub)
+	if err != nil {
+		return "", err
+	}
+	return pk.AccountHash().Hex(), nil
 }
 
 //根据公钥数据生成accountHex
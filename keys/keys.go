@@ -1,10 +1,9 @@
 package keys
 
 import (
-	"encoding/hex"
 	"errors"
 	"fmt"
-	"golang.org/x/crypto/blake2b"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
 )
 
 type SignatureAlgorithm string
@@ -50,31 +49,24 @@ func Sign(private []byte, message []byte, sa SignatureAlgorithm) (sig []byte, er
 
 /*
 write by flynn
+根据地址(公钥hex)计算account hash
 */
 func AddressToAccountHash(address string) ([]byte, error) {
-	pub, err := hex.DecodeString(address)
+	pk, err := cl.ParsePublicKey(address)
 	if err != nil {
 		return nil, err
 	}
-	if len(pub) != 33 {
-		return nil, fmt.Errorf("address length is not equal 33,len=[%d]", len(pub))
-	}
-	calcAccountHash := func(prefix string, rawPublicKey []byte) []byte {
-		var data []byte
-		data = append(data, []byte(prefix)...)
-		data = append(data, 0x00)
-		data = append(data, rawPublicKey...)
-		accountHash := blake2b.Sum256(data)
-		return accountHash[:]
-	}
-	var prefix string
-	if pub[0] == 0x01 {
-		prefix = "ed25519"
-	} else if pub[0] == 0x02 {
-		prefix = "secp256k1"
-	} else {
-		return nil, fmt.Errorf("unkown signature algorithm")
-	}
-	return calcAccountHash(prefix, pub[1:]), nil
+	accountHash := pk.AccountHash()
+	return accountHash.Bytes(), nil
+}
 
+//签名算法对应的公钥前缀
+func AlgorithmTag(sa SignatureAlgorithm) (byte, error) {
+	switch sa {
+	case Ed25519:
+		return cl.Ed25519Tag, nil
+	case Secp256K1:
+		return cl.Secp256K1Tag, nil
+	}
+	return 0, fmt.Errorf("unkown signature algorithm: %s", sa)
 }
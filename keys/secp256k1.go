@@ -2,6 +2,7 @@ package keys
 
 import (
 	"crypto/ecdsa"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -10,6 +11,13 @@ import (
 	"math/big"
 )
 
+const (
+	//节点接受的签名长度 r|s
+	secp256k1SigLen = 64
+	//approval中签名的算法前缀
+	secp256k1SigTag = 0x02
+)
+
 type SECP256K1 struct {
 	//使用此算法生成的密钥对应的账号前缀
 	prefix string
@@ -83,6 +91,7 @@ func (s *SECP256K1) AccountHex() (string, error) {
 	return AccountHex(s.pubKey, s.prefix)
 }
 
+//签名格式与节点一致：对消息做sha256后签名，返回64字节的 r|s（low-S），不包含recovery id
 func (s *SECP256K1) Sign(message []byte) (sig []byte, err error) {
 	if err := CheckPrivKey(s.privateKey, s.privByteLen); err != nil {
 		return nil, err
@@ -91,14 +100,28 @@ func (s *SECP256K1) Sign(message []byte) (sig []byte, err error) {
 	if err != nil {
 		return nil, err
 	}
-	return ethcrypto.Sign(message, priv)
+	digest := sha256.Sum256(message)
+	//返回 r|s|v 65字节，libsecp256k1生成的s已经是low-S
+	rsv, err := ethcrypto.Sign(digest[:], priv)
+	if err != nil {
+		return nil, err
+	}
+	return rsv[:secp256k1SigLen], nil
 }
 
+//sig可以是64字节的 r|s，也可以是approval中带算法前缀02的65字节格式
 func (s *SECP256K1) Verify(message, sig []byte) (bool, error) {
 	if err := CheckPubKey(s.pubKey, s.pubByteLen); err != nil {
 		return false, err
 	}
-	return ethcrypto.VerifySignature(s.pubKey, message, sig[:len(sig)-1]), nil
+	if len(sig) == secp256k1SigLen+1 && sig[0] == secp256k1SigTag {
+		sig = sig[1:]
+	}
+	if len(sig) != secp256k1SigLen {
+		return false, errors.New(fmt.Sprintf("%s Verify:invalid signature len", s.algorithm))
+	}
+	digest := sha256.Sum256(message)
+	return ethcrypto.VerifySignature(s.pubKey, digest[:], sig), nil
 }
 
 func (s *SECP256K1) ParsePrivateKeyToPem() (string, error) {
@@ -1,9 +1,14 @@
 package keys
 
 import (
+	"crypto/ecdsa"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"github.com/JFJun/casperlabs-go/keys/blake2b"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"math/big"
 	"testing"
 )
 
@@ -69,13 +74,40 @@ func TestSECP256K1_Sign(t *testing.T) {
 		t.Fatal(err)
 	}
 	fmt.Println(hex.EncodeToString(sig))
+	if len(sig) != 64 {
+		t.Fatalf("signature len error: %d", len(sig))
+	}
 
-	verify, err := holder.Verify(msg, sig)
+	//使用标准库按sha256摘要验证
+	ecdsaPub, err := ethcrypto.DecompressPubkey(pub)
 	if err != nil {
 		t.Fatal(err)
 	}
-	fmt.Println(verify)
+	digest := sha256.Sum256(msg)
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(ecdsaPub, digest[:], r, s) {
+		t.Fatal("signature is not ecdsa over sha256")
+	}
+	halfN := new(big.Int).Rsh(secp256k1.S256().N, 1)
+	if s.Cmp(halfN) > 0 {
+		t.Fatal("signature is not low-S")
+	}
 
+	verify, err := holder.Verify(msg, sig)
+	if err != nil || !verify {
+		t.Fatal("verify error")
+	}
+	verify, err = holder.Verify(msg, append([]byte{0x02}, sig...))
+	if err != nil || !verify {
+		t.Fatal("verify tagged signature error")
+	}
+	verify, _ = holder.Verify(blake2b.Hash([]byte("other")), sig)
+	if verify {
+		t.Fatal("signature of other message should be rejected")
+	}
+	if _, err = holder.Verify(msg, sig[:63]); err == nil {
+		t.Fatal("short signature should be rejected")
+	}
 }
 
 func getSECP256K1Key() ([]byte, []byte) {
@@ -1,5 +1,7 @@
 package model
 
+import cl "github.com/JFJun/casperlabs-go/clvalue"
+
 type BlockState struct {
 	ApiVersion  string                `json:"api_version"`
 	StoredValue BlockStateStoredValue `json:"stored_value"`
@@ -7,9 +9,25 @@ type BlockState struct {
 
 type BlockStateStoredValue struct {
 	Account BlockStateAccount `json:"Account"`
+	// named key, dictionary item等存储的值
+	CLValue *cl.CLValue `json:"CLValue,omitempty"`
 }
 
 type BlockStateAccount struct {
-	AccountHash string `json:"account_hash"`
-	MainPurse   string `json:"main_purse"`
+	AccountHash cl.AccountHash `json:"account_hash"`
+	MainPurse   *cl.URef       `json:"main_purse"`
+	// 可以代表此账户签名的key，包括账户自身
+	AssociatedKeys   []AssociatedKey  `json:"associated_keys"`
+	ActionThresholds ActionThresholds `json:"action_thresholds"`
+}
+
+type AssociatedKey struct {
+	AccountHash cl.AccountHash `json:"account_hash"`
+	Weight      int            `json:"weight"`
+}
+
+// 发送deploy和管理key所需的签名权重之和
+type ActionThresholds struct {
+	Deployment    int `json:"deployment"`
+	KeyManagement int `json:"key_management"`
 }
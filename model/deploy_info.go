@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/JFJun/casperlabs-go/deploy"
+)
+
+// info_get_deploy的返回结果
+type DeployInfo struct {
+	ApiVersion string `json:"api_version"`
+	// 无法解析时为空,例如节点版本较新包含未知的类型,此时可以从RawDeploy获取原始数据
+	Deploy    *deploy.Deploy  `json:"deploy"`
+	RawDeploy json.RawMessage `json:"-"`
+	// deploy尚未执行时为空,正常情况下只会在一个区块中执行
+	ExecutionResults []BlockExecutionResult `json:"execution_results"`
+}
+
+// deploy解析失败时不返回错误,保证执行结果仍然可以获取
+func (di *DeployInfo) UnmarshalJSON(data []byte) error {
+	var v struct {
+		ApiVersion       string                 `json:"api_version"`
+		Deploy           json.RawMessage        `json:"deploy"`
+		ExecutionResults []BlockExecutionResult `json:"execution_results"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*di = DeployInfo{
+		ApiVersion:       v.ApiVersion,
+		RawDeploy:        v.Deploy,
+		ExecutionResults: v.ExecutionResults,
+	}
+	if len(v.Deploy) != 0 && string(v.Deploy) != "null" {
+		d := new(deploy.Deploy)
+		if err := json.Unmarshal(v.Deploy, d); err == nil {
+			di.Deploy = d
+		}
+	}
+	return nil
+}
+
+type BlockExecutionResult struct {
+	BlockHash string          `json:"block_hash"`
+	Result    ExecutionResult `json:"result"`
+}
+
+// Success和Failure只有一个不为空
+type ExecutionResult struct {
+	Success *ExecutionResultDetail `json:"Success,omitempty"`
+	Failure *ExecutionResultDetail `json:"Failure,omitempty"`
+}
+
+type ExecutionResultDetail struct {
+	Effect ExecutionEffect `json:"effect"`
+	// transfer-<hex>格式的transfer地址
+	Transfers []string `json:"transfers"`
+	// 消耗的gas,单位为motes
+	Cost string `json:"cost"`
+	// 仅Failure有值
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// 是否已经在区块中执行
+func (di *DeployInfo) Executed() bool {
+	return len(di.ExecutionResults) > 0
+}
+
+func (er *ExecutionResult) IsSuccess() bool {
+	return er.Success != nil
+}
+
+// 返回Success或者Failure中的执行详情,都为空时返回nil
+func (er *ExecutionResult) Detail() *ExecutionResultDetail {
+	if er.Success != nil {
+		return er.Success
+	}
+	return er.Failure
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"github.com/JFJun/casperlabs-go/deploy"
+	"math/big"
+	"testing"
+)
+
+func TestDeployInfo_UnmarshalJSON(t *testing.T) {
+	account, _ := cl.ParsePublicKey("0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c")
+	session, _ := deploy.NewTransfer(big.NewInt(2500000000), account, 1)
+	payment, _ := deploy.StandardPayment(big.NewInt(10000))
+	d, err := deploy.MakeDeploy(deploy.NewDeployParam(account, "casper-test"), session, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployJson, _ := json.Marshal(d)
+	data := `{"api_version":"1.0.0","deploy":` + string(deployJson) + `,"execution_results":[
+		{"block_hash":"6f168ef1d9bfcca97146b4925924e9594dba03a3fe30952653867ecc5fda5746","result":{"Success":{
+			"effect":{"operations":[],"transforms":[]},
+			"transfers":["transfer-5de3bc3fb8b2ed1b55ee8bf8b5ad6ea7e26d77fe4e3bd31a83e0e9e0a1d6a0c8"],
+			"cost":"100000000"}}},
+		{"block_hash":"7f168ef1d9bfcca97146b4925924e9594dba03a3fe30952653867ecc5fda5746","result":{"Failure":{
+			"effect":{"operations":[],"transforms":[]},"transfers":[],"cost":"10000",
+			"error_message":"Insufficient payment"}}}]}`
+	var info DeployInfo
+	if err = json.Unmarshal([]byte(data), &info); err != nil {
+		t.Fatal(err)
+	}
+	if err = info.Deploy.CheckHash(); err != nil {
+		t.Fatal(err)
+	}
+	if !info.Executed() || len(info.ExecutionResults) != 2 {
+		t.Fatal("execution results error")
+	}
+	success, failure := info.ExecutionResults[0].Result, info.ExecutionResults[1].Result
+	if !success.IsSuccess() || success.Detail().Cost != "100000000" || len(success.Detail().Transfers) != 1 {
+		t.Fatalf("success result error: %+v", success.Detail())
+	}
+	if failure.IsSuccess() || failure.Detail().ErrorMessage != "Insufficient payment" {
+		t.Fatalf("failure result error: %+v", failure.Detail())
+	}
+}
+
+// the deploy can not be decoded, e.g. an unknown session variant of a newer node
+func TestDeployInfo_UnmarshalJSON_Lenient(t *testing.T) {
+	data := `{"api_version":"1.5.0","deploy":{"hash":"00","session":{"Unknown":{}}},"execution_results":[
+		{"block_hash":"6f168ef1d9bfcca97146b4925924e9594dba03a3fe30952653867ecc5fda5746","result":{"Success":{
+			"effect":{"operations":[],"transforms":[]},"transfers":[],"cost":"100000000"}}}]}`
+	var info DeployInfo
+	if err := json.Unmarshal([]byte(data), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Deploy != nil || len(info.RawDeploy) == 0 {
+		t.Fatalf("deploy error: %v %s", info.Deploy, info.RawDeploy)
+	}
+	if !info.Executed() || info.ExecutionResults[0].Result.Detail().Cost != "100000000" {
+		t.Fatal("execution results error")
+	}
+	var empty ExecutionResult
+	if empty.Detail() != nil {
+		t.Fatal("empty result should have no detail")
+	}
+}
@@ -0,0 +1,260 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	cl "github.com/JFJun/casperlabs-go/clvalue"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// Transform的类型
+const (
+	TransformIdentity             = "Identity"
+	TransformWriteCLValue         = "WriteCLValue"
+	TransformWriteAccount         = "WriteAccount"
+	TransformWriteContractWasm    = "WriteContractWasm"
+	TransformWriteContract        = "WriteContract"
+	TransformWriteContractPackage = "WriteContractPackage"
+	TransformWriteDeployInfo      = "WriteDeployInfo"
+	TransformWriteEraInfo         = "WriteEraInfo"
+	TransformWriteTransfer        = "WriteTransfer"
+	TransformWriteBid             = "WriteBid"
+	TransformWriteWithdraw        = "WriteWithdraw"
+	TransformAddInt32             = "AddInt32"
+	TransformAddUInt64            = "AddUInt64"
+	TransformAddUInt128           = "AddUInt128"
+	TransformAddUInt256           = "AddUInt256"
+	TransformAddUInt512           = "AddUInt512"
+	TransformAddKeys              = "AddKeys"
+	TransformFailure              = "Failure"
+)
+
+const balanceKeyPrefix = "balance-"
+
+// deploy执行对全局状态的影响
+type ExecutionEffect struct {
+	Operations []Operation      `json:"operations"`
+	Transforms []TransformEntry `json:"transforms"`
+}
+
+type Operation struct {
+	Key string `json:"key"`
+	// Read, Write, Add, NoOp
+	Kind string `json:"kind"`
+}
+
+type TransformEntry struct {
+	Key       string    `json:"key"`
+	Transform Transform `json:"transform"`
+}
+
+/*
+Transform只有Kind对应的字段有值,例如Kind为WriteCLValue时只有WriteCLValue不为空
+无法识别的类型或者无法解析的值不会报错,Kind保存类型名称,Raw保存原始json
+*/
+type Transform struct {
+	Kind string `json:"-"`
+
+	WriteCLValue    *cl.CLValue
+	WriteAccount    string
+	WriteDeployInfo *DeployExecutionInfo
+	WriteEraInfo    *EraInfo
+	WriteTransfer   *Transfer
+	WriteBid        *Bid
+	WriteWithdraw   []WithdrawPurse
+	AddInt32        int32
+	AddUInt64       uint64
+	// U128, U256, U512的十进制字符串
+	AddUInt128 string
+	AddUInt256 string
+	AddUInt512 string
+	AddKeys    []NamedKey
+	Failure    string
+
+	Raw json.RawMessage `json:"-"`
+}
+
+type DeployExecutionInfo struct {
+	DeployHash string   `json:"deploy_hash"`
+	Transfers  []string `json:"transfers"`
+	From       string   `json:"from"`
+	Source     string   `json:"source"`
+	Gas        string   `json:"gas"`
+}
+
+type EraInfo struct {
+	SeigniorageAllocations []SeigniorageAllocation `json:"seigniorage_allocations"`
+}
+
+// Validator和Delegator只有一个不为空
+type SeigniorageAllocation struct {
+	Validator *struct {
+		ValidatorPublicKey string `json:"validator_public_key"`
+		Amount             string `json:"amount"`
+	} `json:"Validator,omitempty"`
+	Delegator *struct {
+		DelegatorPublicKey string `json:"delegator_public_key"`
+		ValidatorPublicKey string `json:"validator_public_key"`
+		Amount             string `json:"amount"`
+	} `json:"Delegator,omitempty"`
+}
+
+type Bid struct {
+	ValidatorPublicKey string               `json:"validator_public_key"`
+	BondingPurse       string               `json:"bonding_purse"`
+	StakedAmount       string               `json:"staked_amount"`
+	DelegationRate     uint8                `json:"delegation_rate"`
+	Delegators         map[string]Delegator `json:"delegators"`
+	Inactive           bool                 `json:"inactive"`
+}
+
+type Delegator struct {
+	DelegatorPublicKey string `json:"delegator_public_key"`
+	StakedAmount       string `json:"staked_amount"`
+	BondingPurse       string `json:"bonding_purse"`
+	ValidatorPublicKey string `json:"validator_public_key"`
+}
+
+type WithdrawPurse struct {
+	BondingPurse       string `json:"bonding_purse"`
+	ValidatorPublicKey string `json:"validator_public_key"`
+	UnbonderPublicKey  string `json:"unbonder_public_key"`
+	EraOfCreation      uint64 `json:"era_of_creation"`
+	Amount             string `json:"amount"`
+}
+
+type NamedKey struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+func (t *Transform) UnmarshalJSON(data []byte) error {
+	*t = Transform{Raw: append(json.RawMessage{}, data...)}
+	//没有值的类型是字符串,例如"Identity"
+	var kind string
+	if err := json.Unmarshal(data, &kind); err == nil {
+		t.Kind = kind
+		return nil
+	}
+	var variants map[string]json.RawMessage
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return fmt.Errorf("invalid transform: %s", string(data))
+	}
+	for kind, value := range variants {
+		t.Kind = kind
+		var target interface{}
+		switch kind {
+		case TransformWriteCLValue:
+			target = &t.WriteCLValue
+		case TransformWriteAccount:
+			target = &t.WriteAccount
+		case TransformWriteDeployInfo:
+			target = &t.WriteDeployInfo
+		case TransformWriteEraInfo:
+			target = &t.WriteEraInfo
+		case TransformWriteTransfer:
+			target = &t.WriteTransfer
+		case TransformWriteBid:
+			target = &t.WriteBid
+		case TransformWriteWithdraw:
+			target = &t.WriteWithdraw
+		case TransformAddInt32:
+			target = &t.AddInt32
+		case TransformAddUInt64:
+			target = &t.AddUInt64
+		case TransformAddUInt128:
+			target = &t.AddUInt128
+		case TransformAddUInt256:
+			target = &t.AddUInt256
+		case TransformAddUInt512:
+			target = &t.AddUInt512
+		case TransformAddKeys:
+			target = &t.AddKeys
+		case TransformFailure:
+			target = &t.Failure
+		default:
+			continue
+		}
+		//值无法解析时忽略,可以从Raw中获取原始数据
+		if err := json.Unmarshal(value, target); err != nil {
+			v := reflect.ValueOf(target).Elem()
+			v.Set(reflect.Zero(v.Type()))
+		}
+	}
+	return nil
+}
+
+func (t Transform) MarshalJSON() ([]byte, error) {
+	if t.Raw != nil {
+		return t.Raw, nil
+	}
+	return json.Marshal(t.Kind)
+}
+
+// 一个purse的余额变化
+type BalanceChange struct {
+	// balance-<hex>格式的key
+	Key string
+	// AddUInt512增加的余额之和,例如转账的接收方
+	Added *big.Int
+	// WriteCLValue直接写入的最新余额,例如转账的发送方,没有写入时为空
+	// 发送方的变化量需要用执行前的余额计算
+	Written *big.Int
+}
+
+// 按purse统计余额变化,顺序与transforms中第一次出现的顺序一致
+func (e *ExecutionEffect) BalanceChanges() []*BalanceChange {
+	var changes []*BalanceChange
+	index := make(map[string]*BalanceChange)
+	for _, entry := range e.Transforms {
+		if !strings.HasPrefix(entry.Key, balanceKeyPrefix) {
+			continue
+		}
+		var added, written *big.Int
+		switch entry.Transform.Kind {
+		case TransformAddUInt512:
+			n, ok := new(big.Int).SetString(entry.Transform.AddUInt512, 10)
+			if !ok {
+				continue
+			}
+			added = n
+		case TransformWriteCLValue:
+			if entry.Transform.WriteCLValue == nil {
+				continue
+			}
+			u512, ok := entry.Transform.WriteCLValue.Value().(*cl.U512)
+			if !ok {
+				continue
+			}
+			written = u512.Value()
+		default:
+			continue
+		}
+		change, ok := index[entry.Key]
+		if !ok {
+			change = &BalanceChange{Key: entry.Key, Added: big.NewInt(0)}
+			index[entry.Key] = change
+			changes = append(changes, change)
+		}
+		if added != nil {
+			change.Added.Add(change.Added, added)
+		}
+		if written != nil {
+			change.Written = written
+		}
+	}
+	return changes
+}
+
+// 返回AddKeys新增的named key
+func (e *ExecutionEffect) NamedKeys() []NamedKey {
+	var keys []NamedKey
+	for _, entry := range e.Transforms {
+		if entry.Transform.Kind == TransformAddKeys {
+			keys = append(keys, entry.Transform.AddKeys...)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testEffect = `{
+	"operations": [{"key": "account-hash-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840", "kind": "Read"}],
+	"transforms": [
+		{"key": "account-hash-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840", "transform": "Identity"},
+		{"key": "balance-98d945f5324f865243b7c02c0417ab6eac361c5c56602fd42ced834a1ba201b6", "transform": {"WriteCLValue": {"cl_type": "U512", "bytes": "0400ca9a3b", "parsed": "1000000000"}}},
+		{"key": "balance-fe327f9815a1d016e1143db85e25a86341883949fd75ac1c1e7408a26c5b62ef", "transform": {"AddUInt512": "100000000"}},
+		{"key": "balance-fe327f9815a1d016e1143db85e25a86341883949fd75ac1c1e7408a26c5b62ef", "transform": {"AddUInt512": "2500000000"}},
+		{"key": "transfer-5de3bc3fb8b2ed1b55ee8bf8b5ad6ea7e26d77fe4e3bd31a83e0e9e0a1d6a0c8", "transform": {"WriteTransfer": {
+			"deploy_hash": "20f1190d4ddc06246e07d5fd0454d90f3b509936e3d2584350239104e183a000",
+			"from": "account-hash-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840",
+			"to": null, "source": "uref-98d945f5324f865243b7c02c0417ab6eac361c5c56602fd42ced834a1ba201b6-007",
+			"target": "uref-fe327f9815a1d016e1143db85e25a86341883949fd75ac1c1e7408a26c5b62ef-004",
+			"amount": "2500000000", "gas": "0", "id": 1}}},
+		{"key": "deploy-20f1190d4ddc06246e07d5fd0454d90f3b509936e3d2584350239104e183a000", "transform": {"WriteDeployInfo": {
+			"deploy_hash": "20f1190d4ddc06246e07d5fd0454d90f3b509936e3d2584350239104e183a000",
+			"transfers": ["transfer-5de3bc3fb8b2ed1b55ee8bf8b5ad6ea7e26d77fe4e3bd31a83e0e9e0a1d6a0c8"],
+			"from": "account-hash-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840",
+			"source": "uref-98d945f5324f865243b7c02c0417ab6eac361c5c56602fd42ced834a1ba201b6-007", "gas": "100000000"}}},
+		{"key": "account-hash-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840", "transform": {"AddKeys": [
+			{"name": "cep18_contract_hash", "key": "hash-5de3bc3fb8b2ed1b55ee8bf8b5ad6ea7e26d77fe4e3bd31a83e0e9e0a1d6a0c8"}]}},
+		{"key": "bid-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840", "transform": {"WriteBid": {
+			"validator_public_key": "0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c",
+			"bonding_purse": "uref-98d945f5324f865243b7c02c0417ab6eac361c5c56602fd42ced834a1ba201b6-007",
+			"staked_amount": "1000", "delegation_rate": 10, "delegators": {}, "inactive": false}}},
+		{"key": "withdraw-2c4a6ce0da5d175e9638ec0830e01dd6cf5f4b1fbb0724f7d2d9de12b1e0f840", "transform": {"WriteWithdraw": [{
+			"bonding_purse": "uref-98d945f5324f865243b7c02c0417ab6eac361c5c56602fd42ced834a1ba201b6-007",
+			"validator_public_key": "0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c",
+			"unbonder_public_key": "0178a128a04d0c869867ce761505c47eb254f6da67828811c7e96608b3a28a1e3c",
+			"era_of_creation": 12, "amount": "500"}]}},
+		{"key": "hash-5de3bc3fb8b2ed1b55ee8bf8b5ad6ea7e26d77fe4e3bd31a83e0e9e0a1d6a0c8", "transform": {"WriteCLValue": {"cl_type": "U512", "bytes": "ff"}}},
+		{"key": "hash-5de3bc3fb8b2ed1b55ee8bf8b5ad6ea7e26d77fe4e3bd31a83e0e9e0a1d6a0c8", "transform": {"WriteSomethingNew": {"a": 1}}}
+	]
+}`
+
+func TestExecutionEffect_UnmarshalJSON(t *testing.T) {
+	var effect ExecutionEffect
+	if err := json.Unmarshal([]byte(testEffect), &effect); err != nil {
+		t.Fatal(err)
+	}
+	kinds := []string{TransformIdentity, TransformWriteCLValue, TransformAddUInt512, TransformAddUInt512, TransformWriteTransfer,
+		TransformWriteDeployInfo, TransformAddKeys, TransformWriteBid, TransformWriteWithdraw, TransformWriteCLValue, "WriteSomethingNew"}
+	if len(effect.Transforms) != len(kinds) || len(effect.Operations) != 1 {
+		t.Fatal("transforms len error")
+	}
+	for i, kind := range kinds {
+		if effect.Transforms[i].Transform.Kind != kind {
+			t.Fatalf("transform %d kind error: %s", i, effect.Transforms[i].Transform.Kind)
+		}
+	}
+	tr := effect.Transforms[4].Transform.WriteTransfer
+	if tr == nil || tr.Amount != "2500000000" || tr.Target == "" {
+		t.Fatalf("write transfer error: %+v", tr)
+	}
+	if info := effect.Transforms[5].Transform.WriteDeployInfo; info == nil || info.Gas != "100000000" || len(info.Transfers) != 1 {
+		t.Fatalf("write deploy info error: %+v", info)
+	}
+	if bid := effect.Transforms[7].Transform.WriteBid; bid == nil || bid.DelegationRate != 10 {
+		t.Fatalf("write bid error: %+v", bid)
+	}
+	if w := effect.Transforms[8].Transform.WriteWithdraw; len(w) != 1 || w[0].EraOfCreation != 12 {
+		t.Fatalf("write withdraw error: %+v", w)
+	}
+	// invalid values and unknown variants are kept as raw json
+	if bad := effect.Transforms[9].Transform; bad.WriteCLValue != nil || len(bad.Raw) == 0 {
+		t.Fatal("invalid cl value should be ignored")
+	}
+	data, err := json.Marshal(effect.Transforms[10].Transform)
+	if err != nil || string(data) != `{"WriteSomethingNew":{"a":1}}` {
+		t.Fatalf("raw transform error: %s", data)
+	}
+
+	changes := effect.BalanceChanges()
+	if len(changes) != 2 {
+		t.Fatalf("balance changes len error: %d", len(changes))
+	}
+	if changes[0].Written == nil || changes[0].Written.String() != "1000000000" || changes[0].Added.Sign() != 0 {
+		t.Fatalf("source purse error: %+v", changes[0])
+	}
+	if changes[1].Written != nil || changes[1].Added.String() != "2600000000" {
+		t.Fatalf("target purse error: %+v", changes[1])
+	}
+	keys := effect.NamedKeys()
+	if len(keys) != 1 || keys[0].Name != "cep18_contract_hash" {
+		t.Fatalf("named keys error: %+v", keys)
+	}
+}
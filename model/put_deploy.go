@@ -0,0 +1,6 @@
+package model
+
+type PutDeployResult struct {
+	ApiVersion string `json:"api_version"`
+	DeployHash string `json:"deploy_hash"`
+}
@@ -8,12 +8,11 @@ import (
 )
 
 const (
-	eventStoreApi = "https://event-store-api-clarity-delta.make.services"
-	RpcUrl        = "https://node-clarity-delta.make.services/rpc"
+	RpcUrl = "https://node-clarity-delta.make.services/rpc"
 )
 
 var (
-	casper = client.New(RpcUrl, eventStoreApi)
+	casper = client.New(RpcUrl, "")
 )
 
 func Test_GetStatus(t *testing.T) {
@@ -55,7 +54,17 @@ func Test_GetBlockInfoByHash(t *testing.T) {
 
 func Test_GetDeployByDeployHash(t *testing.T) {
 	txid := "20f1190d4ddc06246e07d5fd0454d90f3b509936e3d2584350239104e183a000"
-	casper.GetDeployByHash(txid)
+	info, err := casper.GetDeployByHash(txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range info.ExecutionResults {
+		detail := r.Result.Detail()
+		if detail == nil {
+			t.Fatalf("empty execution result in block %s", r.BlockHash)
+		}
+		fmt.Println(r.BlockHash, r.Result.IsSuccess(), detail.Cost, detail.ErrorMessage)
+	}
 }
 
 func Test_GetBlockTransfer(t *testing.T) {